@@ -0,0 +1,52 @@
+'use client';
+
+import { useEffect, useRef, useState } from 'react';
+import { useRouter } from 'next/navigation';
+import Link from 'next/link';
+import { useAuth } from '@/context/auth-context';
+
+// The server sends single sign-on logins here with the session tokens in the
+// URL fragment, which browsers never send to a server
+export default function AuthCallbackPage() {
+  const router = useRouter();
+  const { loginWithTokens } = useAuth();
+  const [error, setError] = useState('');
+  const started = useRef(false);
+
+  useEffect(() => {
+    // The tokens work once; strict mode would otherwise use them twice
+    if (started.current) return;
+    started.current = true;
+
+    const params = new URLSearchParams(window.location.hash.slice(1));
+    const token = params.get('token');
+    const refreshToken = params.get('refresh_token');
+    window.history.replaceState(null, '', window.location.pathname);
+
+    if (!token || !refreshToken) {
+      setError('The sign in response was incomplete.');
+      return;
+    }
+
+    loginWithTokens(token, refreshToken)
+      .then(() => router.replace('/'))
+      .catch(() => setError('Signing in failed.'));
+  }, [loginWithTokens, router]);
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50 dark:bg-gray-900">
+      <div className="w-full max-w-md p-8 space-y-6 bg-white dark:bg-gray-800 rounded-lg shadow-lg text-center">
+        {error ? (
+          <>
+            <p className="text-red-500">{error}</p>
+            <Link href="/login" className="text-blue-600 hover:underline">
+              Back to sign in
+            </Link>
+          </>
+        ) : (
+          <p className="text-gray-600 dark:text-gray-400">Signing you in...</p>
+        )}
+      </div>
+    </div>
+  );
+}
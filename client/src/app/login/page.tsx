@@ -1,12 +1,22 @@
 'use client';
 
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import { useRouter } from 'next/navigation';
 import Link from 'next/link';
 import { useAuth } from '@/context/auth-context';
+import { api, OIDCProvider } from '@/lib/api';
 import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
 
+// Errors a single sign-on login comes back with
+const ssoErrors: Record<string, string> = {
+  sso_failed: 'Signing in with your provider failed. Please try again.',
+  sso_cancelled: 'Signing in was cancelled at your provider.',
+  sso_email_unverified: 'Your provider has not verified your email address.',
+  sso_link_unverified:
+    'An account with this email already exists but its address is not verified. Sign in with your password and verify it first.',
+};
+
 export default function LoginPage() {
   const router = useRouter();
   const { login } = useAuth();
@@ -14,6 +24,18 @@ export default function LoginPage() {
   const [password, setPassword] = useState('');
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
+  const [providers, setProviders] = useState<OIDCProvider[]>([]);
+
+  useEffect(() => {
+    const code = new URLSearchParams(window.location.search).get('error');
+    if (code) {
+      setError(ssoErrors[code] ?? ssoErrors.sso_failed);
+    }
+
+    api.getOIDCProviders()
+      .then(setProviders)
+      .catch(() => setProviders([]));
+  }, []);
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -57,9 +79,14 @@ export default function LoginPage() {
           </div>
 
           <div>
-            <label htmlFor="password" className="block text-sm font-medium text-gray-700 dark:text-gray-300">
-              Password
-            </label>
+            <div className="flex items-center justify-between">
+              <label htmlFor="password" className="block text-sm font-medium text-gray-700 dark:text-gray-300">
+                Password
+              </label>
+              <Link href="/forgot-password" className="text-sm text-blue-600 hover:underline">
+                Forgot password?
+              </Link>
+            </div>
             <Input
               id="password"
               type="password"
@@ -80,6 +107,16 @@ export default function LoginPage() {
           </Button>
         </form>
 
+        {providers.length > 0 && (
+          <div className="space-y-2">
+            {providers.map((provider) => (
+              <Button key={provider.id} variant="outline" className="w-full" asChild>
+                <a href={api.oidcLoginURL(provider.id)}>Continue with {provider.name}</a>
+              </Button>
+            ))}
+          </div>
+        )}
+
         <div className="text-center text-sm text-gray-600 dark:text-gray-400">
           Don&apos;t have an account?{' '}
           <Link href="/register" className="text-blue-600 hover:underline">
@@ -8,6 +8,7 @@ interface AuthContextType {
   loading: boolean;
   login: (email: string, password: string) => Promise<void>;
   register: (name: string, email: string, password: string) => Promise<void>;
+  loginWithTokens: (token: string, refreshToken: string) => Promise<void>;
   logout: () => void;
   isAuthenticated: boolean;
 }
@@ -43,9 +44,16 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     setUser(data.user);
   };
 
+  // loginWithTokens finishes a single sign-on login, whose tokens come back
+  // in the callback URL
+  const loginWithTokens = async (token: string, refreshToken: string) => {
+    api.setToken(token, refreshToken);
+    setUser(await api.me());
+  };
+
   const logout = () => {
-    api.logout();
     setUser(null);
+    api.logout();
   };
 
   return (
@@ -55,6 +63,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
         loading,
         login,
         register,
+        loginWithTokens,
         logout,
         isAuthenticated: !!user,
       }}
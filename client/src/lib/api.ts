@@ -6,14 +6,22 @@ interface ApiError {
 
 class ApiClient {
   private token: string | null = null;
+  private refreshToken: string | null = null;
+  private refreshing: Promise<boolean> | null = null;
 
-  setToken(token: string | null) {
+  setToken(token: string | null, refreshToken: string | null = null) {
     this.token = token;
+    this.refreshToken = refreshToken;
     if (token) {
       localStorage.setItem('token', token);
     } else {
       localStorage.removeItem('token');
     }
+    if (refreshToken) {
+      localStorage.setItem('refresh_token', refreshToken);
+    } else {
+      localStorage.removeItem('refresh_token');
+    }
   }
 
   getToken(): string | null {
@@ -23,18 +31,20 @@ class ApiClient {
     return this.token;
   }
 
-  private async request<T>(
-    endpoint: string,
-    options: RequestInit = {}
-  ): Promise<T> {
-    const headers: HeadersInit = {
-      'Content-Type': 'application/json',
-      ...options.headers,
-    };
+  private getRefreshToken(): string | null {
+    if (typeof window !== 'undefined' && !this.refreshToken) {
+      this.refreshToken = localStorage.getItem('refresh_token');
+    }
+    return this.refreshToken;
+  }
 
+  // Access tokens are short-lived; when one is rejected, the refresh token
+  // gets a new pair and the request is sent again once
+  private async send(endpoint: string, options: RequestInit = {}, retry = true): Promise<Response> {
+    const headers = new Headers(options.headers);
     const token = this.getToken();
     if (token) {
-      (headers as Record<string, string>)['Authorization'] = `Bearer ${token}`;
+      headers.set('Authorization', `Bearer ${token}`);
     }
 
     const response = await fetch(`${API_URL}${endpoint}`, {
@@ -42,6 +52,61 @@ class ApiClient {
       headers,
     });
 
+    // Another request may have renewed the tokens while this one was in flight
+    if (response.status === 401 && retry && token && (this.getToken() !== token || (await this.refresh()))) {
+      return this.send(endpoint, options, false);
+    }
+    return response;
+  }
+
+  // refresh renews the tokens. Concurrent callers share one request: the
+  // server rotates the refresh token on use and treats a second use of the
+  // old one as theft, signing the session out.
+  private refresh(): Promise<boolean> {
+    if (!this.refreshing) {
+      this.refreshing = this.renewTokens().finally(() => {
+        this.refreshing = null;
+      });
+    }
+    return this.refreshing;
+  }
+
+  private async renewTokens(): Promise<boolean> {
+    const refreshToken = this.getRefreshToken();
+    if (!refreshToken) {
+      return false;
+    }
+
+    try {
+      const response = await fetch(`${API_URL}/auth/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+      if (!response.ok) {
+        this.setToken(null);
+        return false;
+      }
+      const data: AuthResponse = await response.json();
+      this.setToken(data.token, data.refresh_token);
+      return true;
+    } catch {
+      return false;
+    }
+  }
+
+  private async request<T>(
+    endpoint: string,
+    options: RequestInit = {}
+  ): Promise<T> {
+    const response = await this.send(endpoint, {
+      ...options,
+      headers: {
+        'Content-Type': 'application/json',
+        ...options.headers,
+      },
+    });
+
     if (!response.ok) {
       const errorData: ApiError = await response.json().catch(() => ({ error: 'Unknown error' }));
       throw new Error(errorData.error || `HTTP ${response.status}`);
@@ -56,28 +121,57 @@ class ApiClient {
 
   // Auth endpoints
   async register(name: string, email: string, password: string) {
-    const data = await this.request<{ token: string; user: User }>('/auth/register', {
+    const data = await this.request<AuthResponse>('/auth/register', {
       method: 'POST',
       body: JSON.stringify({ name, email, password }),
     });
-    this.setToken(data.token);
+    this.setToken(data.token, data.refresh_token);
     return data;
   }
 
   async login(email: string, password: string) {
-    const data = await this.request<{ token: string; user: User }>('/auth/login', {
+    const data = await this.request<AuthResponse>('/auth/login', {
       method: 'POST',
       body: JSON.stringify({ email, password }),
     });
-    this.setToken(data.token);
+    this.setToken(data.token, data.refresh_token);
     return data;
   }
 
+  async forgotPassword(email: string) {
+    return this.request<{ message: string }>('/auth/password/forgot', {
+      method: 'POST',
+      body: JSON.stringify({ email }),
+    });
+  }
+
+  async resetPassword(token: string, password: string) {
+    return this.request<{ message: string }>('/auth/password/reset', {
+      method: 'POST',
+      body: JSON.stringify({ token, password }),
+    });
+  }
+
+  async getOIDCProviders() {
+    return this.request<OIDCProvider[]>('/auth/oidc/providers');
+  }
+
+  // oidcLoginURL is where the browser goes to sign in with a provider; it
+  // comes back to /auth/callback
+  oidcLoginURL(providerId: string) {
+    return `${API_URL}/auth/oidc/${encodeURIComponent(providerId)}/login`;
+  }
+
   async me() {
     return this.request<User>('/auth/me');
   }
 
-  logout() {
+  // logout signs the session out on the server too; the local tokens are
+  // dropped either way
+  async logout() {
+    if (this.getToken()) {
+      await this.send('/auth/logout', { method: 'POST' }).catch(() => undefined);
+    }
     this.setToken(null);
   }
 
@@ -127,15 +221,7 @@ class ApiClient {
   }
 
   async downloadExport(id: number, format: 'mysql' | 'postgres' | 'mongo') {
-    const token = this.getToken();
-    const headers: HeadersInit = {};
-    if (token) {
-      headers['Authorization'] = `Bearer ${token}`;
-    }
-
-    const response = await fetch(`${API_URL}/schemas/${id}/download?format=${format}`, {
-      headers,
-    });
+    const response = await this.send(`/schemas/${id}/download?format=${format}`);
 
     if (!response.ok) {
       throw new Error('Download failed');
@@ -166,6 +252,18 @@ class ApiClient {
 }
 
 // Types
+export interface AuthResponse {
+  token: string;
+  refresh_token: string;
+  expires_in: number;
+  user: User;
+}
+
+export interface OIDCProvider {
+  id: string;
+  name: string;
+}
+
 export interface User {
   id: number;
   name: string;
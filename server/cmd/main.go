@@ -7,8 +7,10 @@ import (
 	"github.com/Dragodui/db-schemas-generator/internal/config"
 	"github.com/Dragodui/db-schemas-generator/internal/handler"
 	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/mailer"
 	"github.com/Dragodui/db-schemas-generator/internal/middleware"
 	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/oidc"
 	"github.com/Dragodui/db-schemas-generator/internal/repository"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
@@ -31,18 +33,39 @@ func main() {
 	}
 
 	// auto migrate
-	if err := db.AutoMigrate(&model.User{}, &model.Schema{}); err != nil {
+	if err := db.AutoMigrate(&model.User{}, &model.Schema{}, &model.SchemaVersion{}, &model.Session{}, &model.APIToken{}, &model.Identity{}); err != nil {
 		log.Fatal("failed to migrate:", err)
 	}
 
 	// repos
 	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
 	schemaRepo := repository.NewSchemaRepository(db)
+	versionRepo := repository.NewSchemaVersionRepository(db)
+
+	// mail
+	var mail mailer.Mailer = mailer.NewLogMailer(cfg.MailLogFile)
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+
+	// single sign-on
+	var providers []*oidc.Provider
+	for _, p := range cfg.OIDCProviders {
+		providers = append(providers, oidc.NewProvider(p))
+	}
 
 	// handlers
-	authHandler := handler.NewAuthHandler(userRepo, cfg.JWTSecret)
-	schemaHandler := handler.NewSchemaHandler(schemaRepo)
+	publishPolicy := handler.NewPublishPolicy(userRepo, cfg.RequireVerifiedEmail)
+	authHandler := handler.NewAuthHandler(userRepo, sessionRepo, identityRepo, cfg.JWTSecret, mail, cfg.APIURL, cfg.ClientURL, providers)
+	schemaHandler := handler.NewSchemaHandler(schemaRepo, publishPolicy)
 	exportHandler := handler.NewExportHandler(schemaRepo)
+	importHandler := handler.NewImportHandler(schemaRepo, publishPolicy)
+	validateHandler := handler.NewValidateHandler()
+	versionHandler := handler.NewVersionHandler(schemaRepo, versionRepo)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenRepo)
 
 	// router
 	r := chi.NewRouter()
@@ -69,6 +92,13 @@ func main() {
 		// auth routes
 		r.Post("/auth/register", authHandler.Register)
 		r.Post("/auth/login", authHandler.Login)
+		r.Post("/auth/refresh", authHandler.Refresh)
+		r.Get("/auth/verify", authHandler.VerifyEmail)
+		r.Post("/auth/password/forgot", authHandler.ForgotPassword)
+		r.Post("/auth/password/reset", authHandler.ResetPassword)
+		r.Get("/auth/oidc/providers", authHandler.OIDCProviders)
+		r.Get("/auth/oidc/{provider}/login", authHandler.OIDCLogin)
+		r.Get("/auth/oidc/{provider}/callback", authHandler.OIDCCallback)
 
 		// public schemas
 		r.Get("/schemas/public", schemaHandler.GetPublic)
@@ -76,23 +106,66 @@ func main() {
 		// export without auth (direct)
 		r.Post("/export", exportHandler.ExportDirect)
 
-		// protected routes
+		// import without auth (parse only)
+		r.Post("/import", importHandler.Import)
+
+		// validation without auth
+		r.Post("/validate", validateHandler.Validate)
+
+		// protected routes, for signed in sessions and API tokens
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.JWTAuth(cfg.JWTSecret))
+			r.Use(middleware.APITokenAuth(apiTokenRepo))
+			r.Use(middleware.JWTAuth(cfg.JWTSecret, sessionRepo))
 
-			// auth
 			r.Get("/auth/me", authHandler.Me)
 
-			// schemas CRUD
-			r.Post("/schemas", schemaHandler.Create)
-			r.Get("/schemas", schemaHandler.GetMySchemas)
-			r.Get("/schemas/{id}", schemaHandler.GetByID)
-			r.Put("/schemas/{id}", schemaHandler.Update)
-			r.Delete("/schemas/{id}", schemaHandler.Delete)
+			// account management, for signed in sessions only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireSession)
+
+				r.Post("/auth/verify/request", authHandler.RequestVerification)
+				r.Post("/auth/logout", authHandler.Logout)
+				r.Get("/auth/sessions", authHandler.ListSessions)
+				r.Delete("/auth/sessions/{id}", authHandler.RevokeSession)
+
+				// api tokens
+				r.Post("/auth/tokens", apiTokenHandler.Create)
+				r.Get("/auth/tokens", apiTokenHandler.List)
+				r.Delete("/auth/tokens/{id}", apiTokenHandler.Delete)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireScope(model.ScopeSchemasRead))
+
+				r.Get("/schemas", schemaHandler.GetMySchemas)
+				r.Get("/schemas/{id}", schemaHandler.GetByID)
+
+				// version history
+				r.Get("/schemas/{id}/versions", versionHandler.List)
+				r.Get("/schemas/{id}/versions/{version}", versionHandler.Get)
+				r.Get("/schemas/{id}/diff", versionHandler.Diff)
+				r.Get("/schemas/{id}/migration", versionHandler.Migration)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireScope(model.ScopeSchemasWrite))
+
+				r.Post("/schemas", schemaHandler.Create)
+				r.Put("/schemas/{id}", schemaHandler.Update)
+				r.Delete("/schemas/{id}", schemaHandler.Delete)
+				r.Post("/schemas/{id}/versions/{version}/restore", versionHandler.Restore)
+
+				// import
+				r.Post("/schemas/import", importHandler.ImportAndSave)
+			})
 
 			// export
-			r.Get("/schemas/{id}/export", exportHandler.ExportSchema)
-			r.Get("/schemas/{id}/download", exportHandler.DownloadExport)
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireScope(model.ScopeExport))
+
+				r.Get("/schemas/{id}/export", exportHandler.ExportSchema)
+				r.Get("/schemas/{id}/download", exportHandler.DownloadExport)
+			})
 		})
 	})
 
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/lpernett/godotenv"
 )
@@ -12,6 +13,37 @@ type Config struct {
 	JWTSecret string
 	Port      string
 	ClientURL string
+	// APIURL is the public address of this server, used in links sent by email
+	APIURL string
+
+	// Without SMTPHost, email is written to MailLogFile or the log instead
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	MailLogFile  string
+
+	// RequireVerifiedEmail stops users from publishing schemas before they
+	// have verified their email address
+	RequireVerifiedEmail bool
+
+	// OIDCProviders are the single sign-on providers users can log in with
+	OIDCProviders []OIDCProvider
+}
+
+// OIDCProvider is an OpenID Connect provider, configured through
+// OIDC_<ID>_ISSUER, OIDC_<ID>_CLIENT_ID and so on for each ID listed in
+// OIDC_PROVIDERS
+type OIDCProvider struct {
+	// ID names the provider in URLs
+	ID string
+	// Name is shown on the login page
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
 }
 
 func Load() *Config {
@@ -24,6 +56,16 @@ func Load() *Config {
 		JWTSecret: os.Getenv("JWT_SECRET"),
 		DB_DSN:    os.Getenv("DB_DSN"),
 		ClientURL: os.Getenv("CLIENT_URL"),
+		APIURL:    os.Getenv("API_URL"),
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		MailFrom:     os.Getenv("MAIL_FROM"),
+		MailLogFile:  os.Getenv("MAIL_LOG_FILE"),
+
+		RequireVerifiedEmail: os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true",
 	}
 
 	if cfg.Port == "" {
@@ -34,5 +76,51 @@ func Load() *Config {
 		cfg.ClientURL = "https://localhost:5173"
 	}
 
+	if cfg.APIURL == "" {
+		cfg.APIURL = "http://localhost:" + cfg.Port
+	}
+
+	if cfg.SMTPPort == "" {
+		cfg.SMTPPort = "587"
+	}
+
+	if cfg.MailFrom == "" {
+		cfg.MailFrom = "no-reply@localhost"
+	}
+
+	cfg.OIDCProviders = loadOIDCProviders()
+
 	return cfg
 }
+
+func loadOIDCProviders() []OIDCProvider {
+	var providers []OIDCProvider
+	for _, id := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(id, "-", "_")) + "_"
+		p := OIDCProvider{
+			ID:           id,
+			Name:         os.Getenv(prefix + "NAME"),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if p.Issuer == "" || p.ClientID == "" {
+			log.Printf("OIDC provider %q needs %sISSUER and %sCLIENT_ID; skipping it", id, prefix, prefix)
+			continue
+		}
+		if p.Name == "" {
+			p.Name = id
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+		}
+		providers = append(providers, p)
+	}
+	return providers
+}
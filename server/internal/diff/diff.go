@@ -0,0 +1,462 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+type Kind string
+
+const (
+	KindTableAdded           Kind = "table_added"
+	KindTableRemoved         Kind = "table_removed"
+	KindTableRenamed         Kind = "table_renamed"
+	KindColumnAdded          Kind = "column_added"
+	KindColumnRemoved        Kind = "column_removed"
+	KindTypeChanged          Kind = "type_changed"
+	KindNullabilityChanged   Kind = "nullability_changed"
+	KindDefaultChanged       Kind = "default_changed"
+	KindEnumChanged          Kind = "enum_changed"
+	KindPrimaryKeyChanged    Kind = "primary_key_changed"
+	KindUniqueChanged        Kind = "unique_changed"
+	KindAutoIncrementChanged Kind = "auto_increment_changed"
+	KindForeignKeyAdded      Kind = "foreign_key_added"
+	KindForeignKeyRemoved    Kind = "foreign_key_removed"
+	KindForeignKeyChanged    Kind = "foreign_key_changed"
+	KindIndexAdded           Kind = "index_added"
+	KindIndexRemoved         Kind = "index_removed"
+	KindConstraintAdded      Kind = "constraint_added"
+	KindConstraintRemoved    Kind = "constraint_removed"
+	KindCommentChanged       Kind = "comment_changed"
+)
+
+// Change is a single difference between two schemas. Table is the table's
+// name in the newer schema, except for removed tables. From and To hold the
+// old and new value of whatever changed, rendered as text.
+type Change struct {
+	Kind    Kind   `json:"kind"`
+	Table   string `json:"table"`
+	Column  string `json:"column,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Message string `json:"message"`
+}
+
+// Compare lists the structural changes that turn from into to. Tables are
+// matched by name; a removed and an added table with the same columns are
+// reported as a rename.
+func Compare(from, to model.SchemaData) []Change {
+	c := &comparison{changes: []Change{}}
+
+	oldTables := tablesByName(from.Tables)
+	newTables := tablesByName(to.Tables)
+
+	var removed, added []model.Table
+	for _, table := range from.Tables {
+		if _, ok := newTables[table.Name]; !ok {
+			removed = append(removed, table)
+		}
+	}
+	for _, table := range to.Tables {
+		if _, ok := oldTables[table.Name]; !ok {
+			added = append(added, table)
+		}
+	}
+
+	// renames maps old table names to new ones
+	renames := matchRenames(removed, added)
+
+	for _, table := range removed {
+		if _, ok := renames[table.Name]; !ok {
+			c.add(KindTableRemoved, table.Name, "", "", "", "table %s removed", table.Name)
+		}
+	}
+
+	renamedTo := make(map[string]string, len(renames))
+	for old, name := range renames {
+		renamedTo[name] = old
+	}
+
+	for _, table := range to.Tables {
+		if old, ok := renamedTo[table.Name]; ok {
+			c.add(KindTableRenamed, table.Name, "", old, table.Name, "table %s renamed to %s", old, table.Name)
+			c.compareTables(*oldTables[old], table, renames)
+			continue
+		}
+		if old, ok := oldTables[table.Name]; ok {
+			c.compareTables(*old, table, renames)
+			continue
+		}
+		c.add(KindTableAdded, table.Name, "", "", "", "table %s added", table.Name)
+	}
+
+	return c.changes
+}
+
+type comparison struct {
+	changes []Change
+}
+
+func (c *comparison) add(kind Kind, table, column, from, to, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Kind:    kind,
+		Table:   table,
+		Column:  column,
+		From:    from,
+		To:      to,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func tablesByName(tables []model.Table) map[string]*model.Table {
+	byName := make(map[string]*model.Table, len(tables))
+	for i := range tables {
+		if _, exists := byName[tables[i].Name]; !exists {
+			byName[tables[i].Name] = &tables[i]
+		}
+	}
+	return byName
+}
+
+// matchRenames pairs each removed table with the added table whose columns
+// have the same names and types. A signature shared by more than one removed
+// or added table is ambiguous, so those are left as removals and additions.
+func matchRenames(removed, added []model.Table) map[string]string {
+	removedBySignature := make(map[string]int)
+	for _, table := range removed {
+		removedBySignature[columnSignature(table)]++
+	}
+	addedBySignature := make(map[string][]string)
+	for _, table := range added {
+		sig := columnSignature(table)
+		addedBySignature[sig] = append(addedBySignature[sig], table.Name)
+	}
+
+	renames := make(map[string]string)
+	for _, old := range removed {
+		sig := columnSignature(old)
+		if removedBySignature[sig] == 1 && len(addedBySignature[sig]) == 1 {
+			renames[old.Name] = addedBySignature[sig][0]
+		}
+	}
+	return renames
+}
+
+func columnSignature(table model.Table) string {
+	parts := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		parts[i] = col.Name + " " + typeString(col)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c *comparison) compareTables(old, table model.Table, renames map[string]string) {
+	name := table.Name
+
+	oldColumns := make(map[string]model.Column, len(old.Columns))
+	for _, col := range old.Columns {
+		oldColumns[col.Name] = col
+	}
+	newColumns := make(map[string]bool, len(table.Columns))
+	for _, col := range table.Columns {
+		newColumns[col.Name] = true
+	}
+
+	for _, col := range old.Columns {
+		if !newColumns[col.Name] {
+			c.add(KindColumnRemoved, name, col.Name, columnString(col), "", "column %s.%s removed", name, col.Name)
+		}
+	}
+	for _, col := range table.Columns {
+		if before, ok := oldColumns[col.Name]; ok {
+			c.compareColumns(name, before, col)
+			continue
+		}
+		c.add(KindColumnAdded, name, col.Name, "", columnString(col), "column %s.%s added: %s", name, col.Name, columnString(col))
+	}
+
+	if from, to := primaryKey(old), primaryKey(table); from != to {
+		c.add(KindPrimaryKeyChanged, name, "", from, to, "primary key of %s changed from %s to %s", name, orNone(from), orNone(to))
+	}
+
+	c.compareForeignKeys(old, table, renames)
+	c.compareSets(KindIndexAdded, KindIndexRemoved, name, "index", indexStrings(old), indexStrings(table))
+	c.compareSets(KindConstraintAdded, KindConstraintRemoved, name, "constraint", constraintStrings(old), constraintStrings(table))
+
+	if old.Comment != table.Comment {
+		c.add(KindCommentChanged, name, "", old.Comment, table.Comment, "comment of %s changed", name)
+	}
+}
+
+func (c *comparison) compareColumns(table string, old, col model.Column) {
+	qualified := table + "." + col.Name
+
+	if from, to := typeString(old), typeString(col); from != to {
+		c.add(KindTypeChanged, table, col.Name, from, to, "column %s type changed from %s to %s", qualified, from, to)
+	}
+	if old.NotNull != col.NotNull {
+		from, to := nullability(old), nullability(col)
+		c.add(KindNullabilityChanged, table, col.Name, from, to, "column %s changed from %s to %s", qualified, from, to)
+	}
+	if from, to := defaultString(old), defaultString(col); from != to {
+		c.add(KindDefaultChanged, table, col.Name, from, to, "column %s default changed from %s to %s", qualified, orNone(from), orNone(to))
+	}
+	if from, to := strings.Join(old.EnumValues, ", "), strings.Join(col.EnumValues, ", "); from != to {
+		c.add(KindEnumChanged, table, col.Name, from, to, "column %s enum values changed from [%s] to [%s]", qualified, from, to)
+	}
+	if old.Unique != col.Unique {
+		c.add(KindUniqueChanged, table, col.Name, flag(old.Unique, "UNIQUE"), flag(col.Unique, "UNIQUE"), "column %s %s", qualified, toggled(col.Unique, "unique"))
+	}
+	if old.AutoIncrement != col.AutoIncrement {
+		c.add(KindAutoIncrementChanged, table, col.Name, flag(old.AutoIncrement, "AUTO_INCREMENT"), flag(col.AutoIncrement, "AUTO_INCREMENT"), "column %s %s", qualified, toggled(col.AutoIncrement, "auto increment"))
+	}
+	if old.Comment != col.Comment {
+		c.add(KindCommentChanged, table, col.Name, old.Comment, col.Comment, "comment of column %s changed", qualified)
+	}
+}
+
+// compareForeignKeys matches keys by their columns and referenced table, so
+// that a key whose actions changed is reported once. References to renamed
+// tables are compared under the new name.
+func (c *comparison) compareForeignKeys(old, table model.Table, renames map[string]string) {
+	oldKeys := make(map[string]model.ForeignKey)
+	for _, fk := range old.ForeignKeys {
+		if name, ok := renames[fk.References.Table]; ok {
+			fk.References.Table = name
+		}
+		oldKeys[foreignKeyTarget(fk)] = fk
+	}
+	newKeys := make(map[string]bool)
+	for _, fk := range table.ForeignKeys {
+		newKeys[foreignKeyTarget(fk)] = true
+	}
+
+	for _, fk := range old.ForeignKeys {
+		if name, ok := renames[fk.References.Table]; ok {
+			fk.References.Table = name
+		}
+		if !newKeys[foreignKeyTarget(fk)] {
+			desc := DescribeForeignKey(fk)
+			c.add(KindForeignKeyRemoved, table.Name, "", desc, "", "foreign key %s on %s removed", desc, table.Name)
+		}
+	}
+	for _, fk := range table.ForeignKeys {
+		desc := DescribeForeignKey(fk)
+		before, ok := oldKeys[foreignKeyTarget(fk)]
+		if !ok {
+			c.add(KindForeignKeyAdded, table.Name, "", "", desc, "foreign key %s on %s added", desc, table.Name)
+			continue
+		}
+		if from := DescribeForeignKey(before); from != desc {
+			c.add(KindForeignKeyChanged, table.Name, "", from, desc, "foreign key on %s changed from %s to %s", table.Name, from, desc)
+		}
+	}
+}
+
+// compareSets reports the entries only one side has, for parts of a table
+// that are identified by their whole definition
+func (c *comparison) compareSets(addedKind, removedKind Kind, table, noun string, old, current []string) {
+	oldSet := make(map[string]bool, len(old))
+	for _, s := range old {
+		oldSet[s] = true
+	}
+	newSet := make(map[string]bool, len(current))
+	for _, s := range current {
+		newSet[s] = true
+	}
+
+	for _, s := range old {
+		if !newSet[s] {
+			c.add(removedKind, table, "", s, "", "%s %s on %s removed", noun, s, table)
+		}
+	}
+	for _, s := range current {
+		if !oldSet[s] {
+			c.add(addedKind, table, "", "", s, "%s %s on %s added", noun, s, table)
+		}
+	}
+}
+
+// typeString renders a column type with its arguments, such as
+// varchar(255) or decimal(10,2) unsigned
+func typeString(col model.Column) string {
+	s := col.Type
+	switch {
+	case col.Length > 0:
+		s += fmt.Sprintf("(%d)", col.Length)
+	case col.Precision > 0 && col.Scale > 0:
+		s += fmt.Sprintf("(%d,%d)", col.Precision, col.Scale)
+	case col.Precision > 0:
+		s += fmt.Sprintf("(%d)", col.Precision)
+	}
+	if col.Unsigned {
+		s += " unsigned"
+	}
+	return s
+}
+
+func columnString(col model.Column) string {
+	parts := []string{typeString(col)}
+	if col.PrimaryKey {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if col.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if col.AutoIncrement {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if col.Default != nil {
+		parts = append(parts, "DEFAULT "+*col.Default)
+	}
+	if len(col.EnumValues) > 0 {
+		parts = append(parts, "["+strings.Join(col.EnumValues, ", ")+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+func nullability(col model.Column) string {
+	if col.NotNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+func defaultString(col model.Column) string {
+	if col.Default == nil {
+		return ""
+	}
+	return *col.Default
+}
+
+func primaryKey(table model.Table) string {
+	var cols []string
+	for _, col := range table.Columns {
+		if col.PrimaryKey {
+			cols = append(cols, col.Name)
+		}
+	}
+	if len(cols) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(cols, ", ") + ")"
+}
+
+func foreignKeyTarget(fk model.ForeignKey) string {
+	return fmt.Sprintf("(%s) -> %s(%s)", strings.Join(fk.SourceColumns(), ", "), fk.References.Table, strings.Join(fk.TargetColumns(), ", "))
+}
+
+// DescribeForeignKey renders a foreign key the way changes show it
+func DescribeForeignKey(fk model.ForeignKey) string {
+	s := foreignKeyTarget(fk)
+	if fk.Name != "" {
+		s = fk.Name + " " + s
+	}
+	if fk.OnDelete != "" {
+		s += " ON DELETE " + strings.ToUpper(fk.OnDelete)
+	}
+	if fk.OnUpdate != "" {
+		s += " ON UPDATE " + strings.ToUpper(fk.OnUpdate)
+	}
+	if fk.Match != "" {
+		s += " MATCH " + strings.ToUpper(fk.Match)
+	}
+	if fk.Deferrable {
+		s += " DEFERRABLE"
+		if fk.InitiallyDeferred {
+			s += " INITIALLY DEFERRED"
+		}
+	}
+	return s
+}
+
+// DescribeIndex renders an index the way changes show it
+func DescribeIndex(idx model.Index) string {
+	cols := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		cols[i] = col.Name
+		if col.IsDescending() {
+			cols[i] += " DESC"
+		}
+	}
+	s := "(" + strings.Join(cols, ", ") + ")"
+	if idx.Name != "" {
+		s = idx.Name + " " + s
+	}
+	if idx.Unique {
+		s = "UNIQUE " + s
+	}
+	if idx.Method != "" {
+		s += " USING " + strings.ToLower(idx.Method)
+	}
+	if len(idx.Include) > 0 {
+		s += " INCLUDE (" + strings.Join(idx.Include, ", ") + ")"
+	}
+	if where := strings.TrimSpace(idx.Where); where != "" {
+		s += " WHERE " + where
+	}
+	return s
+}
+
+// DescribeUnique renders a UNIQUE constraint the way changes show it
+func DescribeUnique(u model.Unique) string {
+	s := "UNIQUE (" + strings.Join(u.Columns, ", ") + ")"
+	if u.Name != "" {
+		s = u.Name + " " + s
+	}
+	return s
+}
+
+// DescribeCheck renders a CHECK constraint the way changes show it
+func DescribeCheck(ch model.Check) string {
+	s := "CHECK (" + strings.TrimSpace(ch.Expression) + ")"
+	if ch.Name != "" {
+		s = ch.Name + " " + s
+	}
+	return s
+}
+
+func indexStrings(table model.Table) []string {
+	list := make([]string, len(table.Indexes))
+	for i, idx := range table.Indexes {
+		list[i] = DescribeIndex(idx)
+	}
+	return list
+}
+
+func constraintStrings(table model.Table) []string {
+	var list []string
+	for _, u := range table.Uniques {
+		list = append(list, DescribeUnique(u))
+	}
+	for _, ch := range table.Checks {
+		list = append(list, DescribeCheck(ch))
+	}
+	return list
+}
+
+func flag(set bool, name string) string {
+	if set {
+		return name
+	}
+	return ""
+}
+
+func toggled(set bool, name string) string {
+	if set {
+		return "made " + name
+	}
+	return "no longer " + name
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,237 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func ptr(s string) *string { return &s }
+
+func table(name string, columns ...model.Column) model.Table {
+	return model.Table{Name: name, Columns: columns}
+}
+
+var (
+	id    = model.Column{Name: "id", Type: "INT", PrimaryKey: true}
+	email = model.Column{Name: "email", Type: "VARCHAR", Length: 255, NotNull: true}
+	title = model.Column{Name: "title", Type: "TEXT"}
+)
+
+// summary is a change without its message
+type summary struct {
+	Kind   Kind
+	Table  string
+	Column string
+	From   string
+	To     string
+}
+
+func TestCompare(t *testing.T) {
+	withEmailIndex := table("users", id, email)
+	withEmailIndex.Indexes = []model.Index{{Name: "users_email", Columns: []model.IndexColumn{{Name: "email", Order: "DESC"}}}}
+	withChecks := table("users", id, email)
+	withChecks.Uniques = []model.Unique{{Columns: []string{"email"}}}
+	withChecks.Checks = []model.Check{{Name: "positive", Expression: " id > 0 "}}
+
+	post := table("posts", id, model.Column{Name: "author_id", Type: "INT"})
+	post.ForeignKeys = []model.ForeignKey{{Column: "author_id", References: model.Reference{Table: "users", Column: "id"}}}
+	cascade := post
+	cascade.ForeignKeys = []model.ForeignKey{{Column: "author_id", References: model.Reference{Table: "users", Column: "id"}, OnDelete: "cascade"}}
+	toMembers := post
+	toMembers.ForeignKeys = []model.ForeignKey{{Column: "author_id", References: model.Reference{Table: "members", Column: "id"}}}
+
+	for _, tt := range []struct {
+		name     string
+		from, to []model.Table
+		want     []summary
+	}{
+		{
+			name: "no changes",
+			from: []model.Table{table("users", id, email)},
+			to:   []model.Table{table("users", id, email)},
+		},
+		{
+			name: "table added and removed",
+			from: []model.Table{table("users", id, email)},
+			to:   []model.Table{table("posts", id, title)},
+			want: []summary{
+				{KindTableRemoved, "users", "", "", ""},
+				{KindTableAdded, "posts", "", "", ""},
+			},
+		},
+		{
+			name: "table renamed",
+			from: []model.Table{table("users", id, email), table("posts", id, title)},
+			to:   []model.Table{table("members", id, email), table("posts", id, title)},
+			want: []summary{{KindTableRenamed, "members", "", "users", "members"}},
+		},
+		{
+			name: "rename keeps comparing the table",
+			from: []model.Table{table("users", id, email)},
+			to:   []model.Table{{Name: "members", Columns: []model.Column{id, email}, Comment: "people"}},
+			want: []summary{
+				{KindTableRenamed, "members", "", "users", "members"},
+				{KindCommentChanged, "members", "", "", "people"},
+			},
+		},
+		{
+			name: "two added tables with the signature of a removed one",
+			from: []model.Table{table("users", id, email)},
+			to:   []model.Table{table("members", id, email), table("people", id, email)},
+			want: []summary{
+				{KindTableRemoved, "users", "", "", ""},
+				{KindTableAdded, "members", "", "", ""},
+				{KindTableAdded, "people", "", "", ""},
+			},
+		},
+		{
+			name: "two removed tables with the signature of an added one",
+			from: []model.Table{table("users", id, email), table("people", id, email)},
+			to:   []model.Table{table("members", id, email)},
+			want: []summary{
+				{KindTableRemoved, "users", "", "", ""},
+				{KindTableRemoved, "people", "", "", ""},
+				{KindTableAdded, "members", "", "", ""},
+			},
+		},
+		{
+			name: "different column types are not a rename",
+			from: []model.Table{table("users", id, email)},
+			to:   []model.Table{table("members", id, model.Column{Name: "email", Type: "TEXT"})},
+			want: []summary{
+				{KindTableRemoved, "users", "", "", ""},
+				{KindTableAdded, "members", "", "", ""},
+			},
+		},
+		{
+			name: "columns added and removed",
+			from: []model.Table{table("posts", id, title)},
+			to:   []model.Table{table("posts", id, email)},
+			want: []summary{
+				{KindColumnRemoved, "posts", "title", "TEXT", ""},
+				{KindColumnAdded, "posts", "email", "", "VARCHAR(255) NOT NULL"},
+			},
+		},
+		{
+			name: "column changes",
+			from: []model.Table{table("t", id, model.Column{Name: "c", Type: "INT"})},
+			to: []model.Table{table("t", model.Column{Name: "id", Type: "INT"}, model.Column{
+				Name: "c", Type: "DECIMAL", Precision: 10, Scale: 2, Unsigned: true, NotNull: true,
+				Default: ptr("0"), EnumValues: []string{"a"}, Unique: true, AutoIncrement: true, Comment: "count",
+			})},
+			want: []summary{
+				{KindTypeChanged, "t", "c", "INT", "DECIMAL(10,2) unsigned"},
+				{KindNullabilityChanged, "t", "c", "NULL", "NOT NULL"},
+				{KindDefaultChanged, "t", "c", "", "0"},
+				{KindEnumChanged, "t", "c", "", "a"},
+				{KindUniqueChanged, "t", "c", "", "UNIQUE"},
+				{KindAutoIncrementChanged, "t", "c", "", "AUTO_INCREMENT"},
+				{KindCommentChanged, "t", "c", "", "count"},
+				{KindPrimaryKeyChanged, "t", "", "(id)", ""},
+			},
+		},
+		{
+			name: "foreign key actions changed",
+			from: []model.Table{table("users", id), post},
+			to:   []model.Table{table("users", id), cascade},
+			want: []summary{{KindForeignKeyChanged, "posts", "", "(author_id) -> users(id)", "(author_id) -> users(id) ON DELETE CASCADE"}},
+		},
+		{
+			name: "foreign key follows a renamed table",
+			from: []model.Table{table("users", id, email), post},
+			to:   []model.Table{table("members", id, email), toMembers},
+			want: []summary{{KindTableRenamed, "members", "", "users", "members"}},
+		},
+		{
+			name: "foreign key retargeted",
+			from: []model.Table{table("users", id), table("members", id, email), post},
+			to:   []model.Table{table("users", id), table("members", id, email), toMembers},
+			want: []summary{
+				{KindForeignKeyRemoved, "posts", "", "(author_id) -> users(id)", ""},
+				{KindForeignKeyAdded, "posts", "", "", "(author_id) -> members(id)"},
+			},
+		},
+		{
+			name: "indexes and constraints",
+			from: []model.Table{withEmailIndex},
+			to:   []model.Table{withChecks},
+			want: []summary{
+				{KindIndexRemoved, "users", "", "users_email (email DESC)", ""},
+				{KindConstraintAdded, "users", "", "", "UNIQUE (email)"},
+				{KindConstraintAdded, "users", "", "", "positive CHECK (id > 0)"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(model.SchemaData{Tables: tt.from}, model.SchemaData{Tables: tt.to})
+			got := []summary{}
+			for _, ch := range changes {
+				if ch.Message == "" {
+					t.Errorf("%s change has no message", ch.Kind)
+				}
+				got = append(got, summary{ch.Kind, ch.Table, ch.Column, ch.From, ch.To})
+			}
+			want := tt.want
+			if want == nil {
+				want = []summary{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("changes:\n%v\nwant:\n%v", got, want)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	for _, tt := range []struct{ got, want string }{
+		{
+			DescribeForeignKey(model.ForeignKey{
+				Name: "fk_order", Columns: []string{"a", "b"},
+				References: model.Reference{Table: "orders", Columns: []string{"x", "y"}},
+				OnDelete:   "set null", OnUpdate: "cascade", Match: "full", Deferrable: true, InitiallyDeferred: true,
+			}),
+			"fk_order (a, b) -> orders(x, y) ON DELETE SET NULL ON UPDATE CASCADE MATCH FULL DEFERRABLE INITIALLY DEFERRED",
+		},
+		{
+			DescribeIndex(model.Index{
+				Name: "recent", Unique: true, Method: "BTREE", Include: []string{"title"}, Where: " deleted_at IS NULL ",
+				Columns: []model.IndexColumn{{Name: "user_id"}, {Name: "created_at", Order: "desc"}},
+			}),
+			"UNIQUE recent (user_id, created_at DESC) USING btree INCLUDE (title) WHERE deleted_at IS NULL",
+		},
+		{DescribeUnique(model.Unique{Name: "one_email", Columns: []string{"email", "tenant"}}), "one_email UNIQUE (email, tenant)"},
+		{DescribeCheck(model.Check{Expression: "price >= 0"}), "CHECK (price >= 0)"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	if got := Text(nil); got != "No changes\n" {
+		t.Errorf("Text(nil) = %q", got)
+	}
+
+	changes := Compare(
+		model.SchemaData{Tables: []model.Table{table("users", id, email), table("posts", id)}},
+		model.SchemaData{Tables: []model.Table{table("users", id, email, title), table("tags", id, title)}},
+	)
+	want := "- table posts removed\n" +
+		"+ column users.title added: TEXT\n" +
+		"+ table tags added\n" +
+		"\n3 changes\n"
+	if got := Text(changes); got != want {
+		t.Errorf("Text:\n%s\nwant:\n%s", got, want)
+	}
+
+	renamed := Compare(
+		model.SchemaData{Tables: []model.Table{table("users", id)}},
+		model.SchemaData{Tables: []model.Table{table("members", id)}},
+	)
+	if got, want := Text(renamed), "~ table users renamed to members\n\n1 change\n"; got != want {
+		t.Errorf("Text:\n%s\nwant:\n%s", got, want)
+	}
+}
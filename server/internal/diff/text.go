@@ -0,0 +1,35 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Text renders changes for reading, one per line, marked with + for
+// additions, - for removals and ~ for everything else
+func Text(changes []Change) string {
+	if len(changes) == 0 {
+		return "No changes\n"
+	}
+
+	var sb strings.Builder
+	for _, ch := range changes {
+		sb.WriteString(ch.marker() + " " + ch.Message + "\n")
+	}
+	noun := "changes"
+	if len(changes) == 1 {
+		noun = "change"
+	}
+	sb.WriteString(fmt.Sprintf("\n%d %s\n", len(changes), noun))
+	return sb.String()
+}
+
+func (ch Change) marker() string {
+	switch {
+	case strings.HasSuffix(string(ch.Kind), "_added"):
+		return "+"
+	case strings.HasSuffix(string(ch.Kind), "_removed"):
+		return "-"
+	}
+	return "~"
+}
@@ -0,0 +1,56 @@
+package exporter
+
+import "strings"
+
+// commentLines splits a designer comment into lines for the formats that
+// write comments as source code, dropping trailing blank lines
+func commentLines(comment string) []string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimRight(comment, " \t\r\n"), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return lines
+}
+
+// lineComment writes a comment as lines starting with prefix, such as "//"
+// or "///", indented by indent
+func lineComment(comment, indent, prefix string) string {
+	var sb strings.Builder
+	for _, line := range commentLines(comment) {
+		if line == "" {
+			sb.WriteString(indent + prefix + "\n")
+		} else {
+			sb.WriteString(indent + prefix + " " + line + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// jsDocComment writes a comment as a JSDoc block, which must not contain the
+// closing "*/"
+func jsDocComment(comment, indent string) string {
+	lines := commentLines(comment)
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "*/", "*\\/")
+	}
+	if len(lines) == 1 {
+		return indent + "/** " + lines[0] + " */\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		sb.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	sb.WriteString(indent + " */\n")
+	return sb.String()
+}
+
+// lineBreaks replaces every line terminator of SQL and JavaScript with a
+// space
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\u2028", " ", "\u2029", " ")
+
+// singleLine keeps a value written into a line comment from ending it, so
+// that nothing after a line break runs as code
+func singleLine(s string) string {
+	return lineBreaks.Replace(s)
+}
@@ -0,0 +1,435 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// uniqueClause renders a table-level UNIQUE constraint
+func uniqueClause(u model.Unique, quote func(string) string) string {
+	clause := fmt.Sprintf("UNIQUE (%s)", quoteList(u.Columns, quote))
+	if u.Name != "" {
+		clause = fmt.Sprintf("CONSTRAINT %s %s", quote(u.Name), clause)
+	}
+	return clause
+}
+
+// checkClause renders a CHECK constraint. The expression is SQL written by
+// the designer and is passed through as is.
+func checkClause(ch model.Check, quote func(string) string) string {
+	clause := fmt.Sprintf("CHECK (%s)", strings.TrimSpace(ch.Expression))
+	if ch.Name != "" {
+		clause = fmt.Sprintf("CONSTRAINT %s %s", quote(ch.Name), clause)
+	}
+	return clause
+}
+
+// checkLabel names a check in comments, falling back to its expression
+func checkLabel(ch model.Check) string {
+	if ch.Name != "" {
+		return ch.Name
+	}
+	return strings.TrimSpace(ch.Expression)
+}
+
+// createUniqueMongo renders a UNIQUE constraint as a compound unique index
+func createUniqueMongo(table string, u model.Unique) string {
+	options := "unique: true"
+	if u.Name != "" {
+		options = "name: " + quoteJS(u.Name) + ", " + options
+	}
+	return fmt.Sprintf("%s.createIndex(%s, { %s });", mongoCollection(table), mongoIndexKeys(u.Columns), options)
+}
+
+// mongoCheckRules translates a CHECK expression into $jsonSchema subschemas,
+// one per AND-ed condition, for use in allOf. Comparisons of a column with a
+// literal, [NOT] IN lists, BETWEEN and comparisons of LENGTH(column) are
+// supported; ok is false when any part of the expression is something else.
+func mongoCheckRules(expr string) (rules []string, ok bool) {
+	tokens, ok := lexCheck(expr)
+	if !ok || len(tokens) == 0 {
+		return nil, false
+	}
+	p := &checkParser{tokens: tokens}
+	rules, ok = p.conjunction()
+	if !ok || p.pos != len(p.tokens) {
+		return nil, false
+	}
+	return rules, true
+}
+
+type checkTokenKind int
+
+const (
+	checkIdent checkTokenKind = iota
+	checkNumber
+	checkString
+	checkPunct
+)
+
+type checkToken struct {
+	kind checkTokenKind
+	text string
+}
+
+// lexCheck splits a CHECK expression into tokens, accepting the identifier
+// quoting of every SQL dialect
+func lexCheck(expr string) ([]checkToken, bool) {
+	var tokens []checkToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, checkToken{checkIdent, string(runes[start:i])})
+
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, checkToken{checkNumber, string(runes[start:i])})
+
+		case r == '"' || r == '`' || r == '[' || r == '\'':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, false
+				}
+				if runes[i] == closing {
+					// A doubled closing quote stands for itself
+					if closing != ']' && i+1 < len(runes) && runes[i+1] == closing {
+						sb.WriteRune(closing)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			kind := checkIdent
+			if r == '\'' {
+				kind = checkString
+			}
+			tokens = append(tokens, checkToken{kind, sb.String()})
+
+		case i+1 < len(runes) && checkOperators[string(runes[i:i+2])]:
+			tokens = append(tokens, checkToken{checkPunct, string(runes[i : i+2])})
+			i += 2
+
+		case strings.ContainsRune("=<>(),-", r):
+			tokens = append(tokens, checkToken{checkPunct, string(r)})
+			i++
+
+		default:
+			return nil, false
+		}
+	}
+	return tokens, true
+}
+
+var checkOperators = map[string]bool{">=": true, "<=": true, "<>": true, "!=": true}
+
+type checkParser struct {
+	tokens []checkToken
+	pos    int
+}
+
+func (p *checkParser) peek() (checkToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return checkToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *checkParser) acceptPunct(text string) bool {
+	if t, ok := p.peek(); ok && t.kind == checkPunct && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *checkParser) acceptWords(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		t := p.tokens[p.pos+i]
+		if t.kind != checkIdent || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *checkParser) conjunction() ([]string, bool) {
+	var rules []string
+	for {
+		r, ok := p.condition()
+		if !ok {
+			return nil, false
+		}
+		rules = append(rules, r...)
+		if !p.acceptWords("and") {
+			return rules, true
+		}
+	}
+}
+
+func (p *checkParser) condition() ([]string, bool) {
+	if p.acceptPunct("(") {
+		rules, ok := p.conjunction()
+		if !ok || !p.acceptPunct(")") {
+			return nil, false
+		}
+		return rules, true
+	}
+
+	// LENGTH(column) compared with a whole number
+	if p.atLengthFunction() {
+		p.pos += 2
+		column, ok := p.column()
+		if !ok || !p.acceptPunct(")") {
+			return nil, false
+		}
+		op, ok := p.operator()
+		if !ok {
+			return nil, false
+		}
+		value, ok := p.literal()
+		if !ok {
+			return nil, false
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		return lengthRule(column, op, n)
+	}
+
+	// A literal on the left is compared the other way round
+	if value, ok := p.literal(); ok {
+		op, ok := p.operator()
+		if !ok {
+			return nil, false
+		}
+		column, ok := p.column()
+		if !ok {
+			return nil, false
+		}
+		return comparisonRule(column, flipOperator(op), value)
+	}
+
+	column, ok := p.column()
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case p.acceptWords("not", "in"):
+		values, ok := p.literalList()
+		if !ok {
+			return nil, false
+		}
+		return []string{mongoPropertyRule(column, fmt.Sprintf("not: { enum: [%s] }", strings.Join(values, ", ")))}, true
+
+	case p.acceptWords("in"):
+		values, ok := p.literalList()
+		if !ok {
+			return nil, false
+		}
+		return []string{mongoPropertyRule(column, fmt.Sprintf("enum: [%s]", strings.Join(values, ", ")))}, true
+
+	case p.acceptWords("between"):
+		low, ok := p.literal()
+		if !ok || !isNumericLiteral(low) || !p.acceptWords("and") {
+			return nil, false
+		}
+		high, ok := p.literal()
+		if !ok || !isNumericLiteral(high) {
+			return nil, false
+		}
+		return []string{mongoPropertyRule(column, fmt.Sprintf("minimum: %s, maximum: %s", low, high))}, true
+	}
+
+	op, ok := p.operator()
+	if !ok {
+		return nil, false
+	}
+	value, ok := p.literal()
+	if !ok {
+		return nil, false
+	}
+	return comparisonRule(column, op, value)
+}
+
+// atLengthFunction reports whether a call to one of the string length
+// functions of the SQL dialects comes next
+func (p *checkParser) atLengthFunction() bool {
+	if p.pos+1 >= len(p.tokens) || p.tokens[p.pos].kind != checkIdent || p.tokens[p.pos+1].text != "(" {
+		return false
+	}
+	switch strings.ToLower(p.tokens[p.pos].text) {
+	case "length", "char_length", "character_length", "len":
+		return true
+	}
+	return false
+}
+
+func (p *checkParser) column() (string, bool) {
+	t, ok := p.peek()
+	if !ok || t.kind != checkIdent {
+		return "", false
+	}
+	switch strings.ToLower(t.text) {
+	case "and", "or", "not", "in", "between", "null", "true", "false":
+		return "", false
+	}
+	p.pos++
+	return t.text, true
+}
+
+func (p *checkParser) operator() (string, bool) {
+	for _, op := range []string{">=", "<=", "<>", "!=", "=", "<", ">"} {
+		if p.acceptPunct(op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// literal reads a number, string or boolean and returns it as a JavaScript
+// literal
+func (p *checkParser) literal() (string, bool) {
+	negative := p.acceptPunct("-")
+	t, ok := p.peek()
+	if !ok {
+		return "", false
+	}
+	switch {
+	case t.kind == checkNumber && isNumericLiteral(t.text):
+		p.pos++
+		if negative {
+			return "-" + t.text, true
+		}
+		return t.text, true
+	case negative:
+		return "", false
+	case t.kind == checkString:
+		p.pos++
+		return quoteJS(t.text), true
+	case t.kind == checkIdent && (strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false")):
+		p.pos++
+		return strings.ToLower(t.text), true
+	}
+	return "", false
+}
+
+func (p *checkParser) literalList() ([]string, bool) {
+	if !p.acceptPunct("(") {
+		return nil, false
+	}
+	var values []string
+	for {
+		v, ok := p.literal()
+		if !ok {
+			return nil, false
+		}
+		values = append(values, v)
+		if p.acceptPunct(")") {
+			return values, true
+		}
+		if !p.acceptPunct(",") {
+			return nil, false
+		}
+	}
+}
+
+func flipOperator(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case ">":
+		return "<"
+	case "<=":
+		return ">="
+	case ">=":
+		return "<="
+	}
+	return op
+}
+
+// comparisonRule uses the draft 4 keywords MongoDB supports, where
+// exclusiveMinimum and exclusiveMaximum are flags on minimum and maximum
+func comparisonRule(column, op, value string) ([]string, bool) {
+	var keywords string
+	switch op {
+	case "=":
+		keywords = fmt.Sprintf("enum: [%s]", value)
+	case "<>", "!=":
+		keywords = fmt.Sprintf("not: { enum: [%s] }", value)
+	default:
+		// Ordering only translates for numbers
+		if !isNumericLiteral(value) {
+			return nil, false
+		}
+		switch op {
+		case ">=":
+			keywords = "minimum: " + value
+		case ">":
+			keywords = fmt.Sprintf("minimum: %s, exclusiveMinimum: true", value)
+		case "<=":
+			keywords = "maximum: " + value
+		case "<":
+			keywords = fmt.Sprintf("maximum: %s, exclusiveMaximum: true", value)
+		}
+	}
+	return []string{mongoPropertyRule(column, keywords)}, true
+}
+
+func lengthRule(column, op string, n int) ([]string, bool) {
+	var keywords string
+	switch op {
+	case ">=":
+		keywords = fmt.Sprintf("minLength: %d", n)
+	case ">":
+		keywords = fmt.Sprintf("minLength: %d", n+1)
+	case "<=":
+		keywords = fmt.Sprintf("maxLength: %d", n)
+	case "<":
+		if n == 0 {
+			return nil, false
+		}
+		keywords = fmt.Sprintf("maxLength: %d", n-1)
+	case "=":
+		keywords = fmt.Sprintf("minLength: %d, maxLength: %d", n, n)
+	default:
+		return nil, false
+	}
+	return []string{mongoPropertyRule(column, keywords)}, true
+}
+
+func mongoPropertyRule(column, keywords string) string {
+	return fmt.Sprintf("{ properties: { %s: { %s } } }", quoteJS(column), keywords)
+}
@@ -0,0 +1,235 @@
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+var dbmlBareNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func exportDBML(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("// DBML Schema Export\n")
+	sb.WriteString("// Generated by DB Schema Generator\n\n")
+
+	// Enums are declared as standalone types, named like the Postgres ones
+	for _, table := range schema.Tables {
+		for _, col := range table.Columns {
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				sb.WriteString(fmt.Sprintf("Enum %s {\n", dbmlName(dbmlEnumName(table.Name, col.Name))))
+				for _, v := range col.EnumValues {
+					sb.WriteString(fmt.Sprintf("  %s\n", dbmlName(v)))
+				}
+				sb.WriteString("}\n\n")
+			}
+		}
+	}
+
+	for _, table := range schema.Tables {
+		sb.WriteString(fmt.Sprintf("Table %s {\n", dbmlName(table.Name)))
+
+		var primaryKeys []string
+		for _, col := range table.Columns {
+			if col.PrimaryKey {
+				primaryKeys = append(primaryKeys, dbmlName(col.Name))
+			}
+		}
+
+		for _, col := range table.Columns {
+			colType := dbmlType(sizedType(col.Type, col, 0))
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				colType = dbmlName(dbmlEnumName(table.Name, col.Name))
+			}
+
+			var settings []string
+			if col.PrimaryKey && len(primaryKeys) == 1 {
+				settings = append(settings, "pk")
+			}
+			if col.AutoIncrement {
+				settings = append(settings, "increment")
+			}
+			if col.NotNull && !col.PrimaryKey {
+				settings = append(settings, "not null")
+			}
+			if col.Unique && !col.PrimaryKey {
+				settings = append(settings, "unique")
+			}
+			if col.Default != nil {
+				settings = append(settings, "default: "+formatDefaultDBML(*col.Default, col.Type))
+			}
+			if col.Comment != "" {
+				settings = append(settings, "note: "+dbmlNote(col.Comment))
+			}
+
+			line := fmt.Sprintf("  %s %s", dbmlName(col.Name), colType)
+			if len(settings) > 0 {
+				line += fmt.Sprintf(" [%s]", strings.Join(settings, ", "))
+			}
+			sb.WriteString(line + "\n")
+		}
+
+		var indexes []string
+		if len(primaryKeys) > 1 {
+			indexes = append(indexes, fmt.Sprintf("(%s) [pk]", strings.Join(primaryKeys, ", ")))
+		}
+		for _, u := range table.Uniques {
+			line := dbmlColumnList(u.Columns)
+			if u.Name != "" {
+				line += fmt.Sprintf(" [name: %s, unique]", dbmlString(u.Name))
+			} else {
+				line += " [unique]"
+			}
+			indexes = append(indexes, line)
+		}
+		for _, idx := range table.Indexes {
+			indexes = append(indexes, dbmlIndex(idx))
+		}
+		if len(indexes) > 0 {
+			sb.WriteString("\n  indexes {\n")
+			for _, line := range indexes {
+				sb.WriteString("    " + line + "\n")
+			}
+			sb.WriteString("  }\n")
+		}
+
+		if table.Comment != "" {
+			sb.WriteString("\n  Note: " + dbmlNote(table.Comment) + "\n")
+		}
+
+		if len(table.Checks) > 0 {
+			sb.WriteString("\n  checks {\n")
+			for _, ch := range table.Checks {
+				line := "`" + strings.ReplaceAll(strings.TrimSpace(ch.Expression), "`", "") + "`"
+				if ch.Name != "" {
+					line += fmt.Sprintf(" [name: %s]", dbmlString(ch.Name))
+				}
+				sb.WriteString("    " + line + "\n")
+			}
+			sb.WriteString("  }\n")
+		}
+
+		sb.WriteString("}\n\n")
+	}
+
+	for _, table := range schema.Tables {
+		for _, fk := range table.ForeignKeys {
+			// A unique foreign key column makes the relationship one-to-one
+			op := ">"
+			for _, col := range table.Columns {
+				if !fk.IsComposite() && col.Name == fk.Column && col.Unique {
+					op = "-"
+				}
+			}
+
+			ref := "Ref"
+			if fk.Name != "" {
+				ref += " " + dbmlName(fk.Name)
+			}
+			ref += fmt.Sprintf(": %s.%s %s %s.%s",
+				dbmlName(table.Name), dbmlColumnList(fk.SourceColumns()), op,
+				dbmlName(fk.References.Table), dbmlColumnList(fk.TargetColumns()))
+
+			var settings []string
+			if fk.OnDelete != "" {
+				settings = append(settings, "delete: "+strings.ToLower(fk.OnDelete))
+			}
+			if fk.OnUpdate != "" {
+				settings = append(settings, "update: "+strings.ToLower(fk.OnUpdate))
+			}
+			if len(settings) > 0 {
+				ref += fmt.Sprintf(" [%s]", strings.Join(settings, ", "))
+			}
+			sb.WriteString(ref + "\n")
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// dbmlColumnList writes one column bare and several in the (a, b) form of
+// composite refs
+func dbmlColumnList(columns []string) string {
+	if len(columns) == 1 {
+		return dbmlName(columns[0])
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = dbmlName(col)
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// dbmlIndex renders an index line. DBML has no sort order, partial or
+// INCLUDE settings, so those are dropped.
+func dbmlIndex(idx model.Index) string {
+	line := dbmlColumnList(idx.ColumnNames())
+
+	var settings []string
+	if idx.Name != "" {
+		settings = append(settings, "name: "+dbmlString(idx.Name))
+	}
+	if idx.Unique {
+		settings = append(settings, "unique")
+	}
+	if method := strings.ToLower(idx.Method); method == "btree" || method == "hash" {
+		settings = append(settings, "type: "+method)
+	}
+	if len(settings) > 0 {
+		line += fmt.Sprintf(" [%s]", strings.Join(settings, ", "))
+	}
+	return line
+}
+
+func dbmlEnumName(table, column string) string {
+	return fmt.Sprintf("%s_%s_enum", table, column)
+}
+
+// dbmlName double-quotes names that are not plain identifiers
+func dbmlName(s string) string {
+	if dbmlBareNameRe.MatchString(s) {
+		return s
+	}
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// dbmlType keeps types such as varchar(255) bare and quotes multi-word ones
+func dbmlType(t string) string {
+	if strings.Contains(t, " ") {
+		return dbmlName(t)
+	}
+	return t
+}
+
+func formatDefaultDBML(def string, colType string) string {
+	upper := strings.ToUpper(def)
+	if upper == "NULL" {
+		return "null"
+	}
+	if upper == "TRUE" || upper == "FALSE" {
+		return strings.ToLower(def)
+	}
+	if (upper == "CURRENT_TIMESTAMP" || strings.HasSuffix(def, ")")) && !strings.Contains(def, "`") {
+		return "`" + def + "`"
+	}
+	if isNumericLiteral(def) {
+		return def
+	}
+	return dbmlString(def)
+}
+
+// dbmlNote writes a note, using a multi-line string when it spans lines
+func dbmlNote(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "'''") {
+		return "'''" + s + "'''"
+	}
+	return dbmlString(s)
+}
+
+// dbmlString single-quotes a string value
+func dbmlString(s string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`) + "'"
+}
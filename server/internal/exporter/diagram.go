@@ -0,0 +1,183 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// erCardinality describes both ends of a relationship in crow's foot notation.
+// parent and child are the markers written next to the referenced table and
+// the referencing table respectively.
+type erCardinality struct {
+	parent      string
+	child       string
+	identifying bool
+}
+
+// inferCardinality derives the relationship for a foreign key from the
+// constraints on its column: NOT NULL makes the parent mandatory, UNIQUE (or
+// being the sole primary key) limits the child side to one row.
+func inferCardinality(table model.Table, fk model.ForeignKey) erCardinality {
+	card := erCardinality{parent: "|o", child: "o{"}
+
+	pkCount := 0
+	for _, col := range table.Columns {
+		if col.PrimaryKey {
+			pkCount++
+		}
+	}
+
+	source := fk.SourceColumns()
+	mandatory, identifying := len(source) > 0, len(source) > 0
+	for _, name := range source {
+		col := findTableColumn(table, name)
+		if col == nil {
+			return card
+		}
+		mandatory = mandatory && (col.NotNull || col.PrimaryKey)
+		identifying = identifying && col.PrimaryKey
+		if len(source) == 1 && (col.Unique || (col.PrimaryKey && pkCount == 1)) {
+			card.child = "o|"
+		}
+	}
+	// A composite key covering the whole primary key is one-to-one as well
+	if identifying && len(source) == pkCount {
+		card.child = "o|"
+	}
+	if mandatory {
+		card.parent = "||"
+	}
+	card.identifying = identifying
+
+	return card
+}
+
+func erKeyMarkers(table model.Table, col model.Column) []string {
+	var keys []string
+	if col.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	for _, fk := range table.ForeignKeys {
+		if containsString(fk.SourceColumns(), col.Name) {
+			keys = append(keys, "FK")
+			break
+		}
+	}
+	if col.Unique && !col.PrimaryKey {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+func exportMermaid(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("%% Mermaid ER Diagram Export\n")
+	sb.WriteString("%% Generated by DB Schema Generator\n\n")
+	sb.WriteString("erDiagram\n")
+
+	for _, table := range schema.Tables {
+		sb.WriteString(fmt.Sprintf("    %s {\n", sanitizeIdentifier(table.Name)))
+		for _, col := range table.Columns {
+			line := fmt.Sprintf("        %s %s", mermaidType(col.Type), sanitizeIdentifier(col.Name))
+			if keys := erKeyMarkers(table, col); len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, table := range schema.Tables {
+		for _, fk := range table.ForeignKeys {
+			card := inferCardinality(table, fk)
+			line := ".."
+			if card.identifying {
+				line = "--"
+			}
+			sb.WriteString(fmt.Sprintf("    %s %s%s%s %s : \"%s\"\n",
+				sanitizeIdentifier(fk.References.Table), card.parent, line, card.child,
+				sanitizeIdentifier(table.Name), diagramLabel(strings.Join(fk.SourceColumns(), ", "))))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func exportPlantUML(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("' PlantUML ER Diagram Export\n")
+	sb.WriteString("' Generated by DB Schema Generator\n\n")
+	sb.WriteString("@startuml\n")
+	sb.WriteString("hide circle\n")
+	sb.WriteString("skinparam linetype ortho\n\n")
+
+	for _, table := range schema.Tables {
+		sb.WriteString(fmt.Sprintf("entity \"%s\" as %s {\n", diagramLabel(table.Name), sanitizeIdentifier(table.Name)))
+
+		// Key columns go above the separator, as in the usual IE notation
+		var keyCols, otherCols []string
+		for _, col := range table.Columns {
+			line := "  "
+			if col.NotNull || col.PrimaryKey {
+				line += "* "
+			}
+			line += fmt.Sprintf("%s : %s", diagramLabel(col.Name), diagramLabel(strings.ToUpper(col.Type)))
+			if keys := erKeyMarkers(table, col); len(keys) > 0 {
+				line += " <<" + strings.Join(keys, ", ") + ">>"
+			}
+			if col.PrimaryKey {
+				keyCols = append(keyCols, line)
+			} else {
+				otherCols = append(otherCols, line)
+			}
+		}
+
+		for _, line := range keyCols {
+			sb.WriteString(line + "\n")
+		}
+		if len(keyCols) > 0 {
+			sb.WriteString("  --\n")
+		}
+		for _, line := range otherCols {
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	for _, table := range schema.Tables {
+		for _, fk := range table.ForeignKeys {
+			card := inferCardinality(table, fk)
+			line := ".."
+			if card.identifying {
+				line = "--"
+			}
+			sb.WriteString(fmt.Sprintf("%s %s%s%s %s : %s\n",
+				sanitizeIdentifier(fk.References.Table), card.parent, line, card.child,
+				sanitizeIdentifier(table.Name), diagramLabel(strings.Join(fk.SourceColumns(), ", "))))
+		}
+	}
+
+	sb.WriteString("@enduml\n")
+
+	return sb.String(), nil
+}
+
+// mermaidType turns a column type into a single word Mermaid accepts,
+// e.g. "DOUBLE PRECISION" -> "double_precision"
+func mermaidType(t string) string {
+	word := strings.Trim(sanitizeIdentifier(strings.ToLower(t)), "_")
+	if word == "" {
+		return "unknown"
+	}
+	return word
+}
+
+// diagramLabel makes a name safe inside a diagram label, where neither format
+// supports escapes
+func diagramLabel(s string) string {
+	return strings.NewReplacer(`"`, "'", "\n", " ", "\r", " ").Replace(s)
+}
@@ -2,6 +2,7 @@ package exporter
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/Dragodui/db-schemas-generator/internal/model"
@@ -10,12 +11,39 @@ import (
 type ExportFormat string
 
 const (
-	FormatMySQL    ExportFormat = "mysql"
-	FormatPostgres ExportFormat = "postgres"
-	FormatMongo    ExportFormat = "mongo"
+	FormatMySQL      ExportFormat = "mysql"
+	FormatPostgres   ExportFormat = "postgres"
+	FormatMongo      ExportFormat = "mongo"
+	FormatSQLite     ExportFormat = "sqlite"
+	FormatMSSQL      ExportFormat = "mssql"
+	FormatPrisma     ExportFormat = "prisma"
+	FormatGo         ExportFormat = "go"
+	FormatTypeScript ExportFormat = "typescript"
+	FormatZod        ExportFormat = "zod"
+	FormatMermaid    ExportFormat = "mermaid"
+	FormatPlantUML   ExportFormat = "plantuml"
+	FormatDBML       ExportFormat = "dbml"
 )
 
+// Options tweaks the output of the formats that support it
+type Options struct {
+	// Package is the package clause of generated Go code
+	Package string
+	// Tags selects the struct tag styles of generated Go code: gorm, db, json
+	Tags []string
+	// NullTypes makes nullable Go fields use sql.Null* instead of pointers
+	NullTypes bool
+}
+
 func Export(schema model.SchemaData, format ExportFormat) (string, error) {
+	return ExportWithOptions(schema, format, Options{})
+}
+
+func ExportWithOptions(schema model.SchemaData, format ExportFormat, opts Options) (string, error) {
+	if err := checkTypes(schema); err != nil {
+		return "", err
+	}
+
 	switch format {
 	case FormatMySQL:
 		return exportMySQL(schema)
@@ -23,6 +51,24 @@ func Export(schema model.SchemaData, format ExportFormat) (string, error) {
 		return exportPostgres(schema)
 	case FormatMongo:
 		return exportMongo(schema)
+	case FormatSQLite:
+		return exportSQLite(schema)
+	case FormatMSSQL:
+		return exportMSSQL(schema)
+	case FormatPrisma:
+		return exportPrisma(schema)
+	case FormatGo:
+		return exportGo(schema, opts)
+	case FormatTypeScript:
+		return exportTypeScript(schema)
+	case FormatZod:
+		return exportZod(schema)
+	case FormatMermaid:
+		return exportMermaid(schema)
+	case FormatPlantUML:
+		return exportPlantUML(schema)
+	case FormatDBML:
+		return exportDBML(schema)
 	default:
 		return "", fmt.Errorf("unsupported format: %s", format)
 	}
@@ -34,75 +80,105 @@ func exportMySQL(schema model.SchemaData) (string, error) {
 	sb.WriteString("-- MySQL Schema Export\n")
 	sb.WriteString("-- Generated by DB Schema Generator\n\n")
 
-	for _, table := range schema.Tables {
-		sb.WriteString(fmt.Sprintf("CREATE TABLE `%s` (\n", table.Name))
+	tables, deferred := orderTables(schema.Tables)
 
-		var columns []string
-		var primaryKeys []string
+	for _, table := range tables {
+		sb.WriteString(createTableMySQL(table) + "\n")
+	}
 
-		for _, col := range table.Columns {
-			colDef := fmt.Sprintf("  `%s` %s", col.Name, mapTypeToMySQL(col.Type))
+	// Foreign keys in cycles are added once both ends exist
+	for _, d := range deferred {
+		sb.WriteString(fmt.Sprintf("ALTER TABLE %s ADD %s;\n", quoteIdentMySQL(d.Table), foreignKeyMySQL(d.ForeignKey)))
+	}
 
-			if col.NotNull {
-				colDef += " NOT NULL"
-			}
+	return sb.String(), nil
+}
 
-			if col.AutoIncrement {
-				colDef += " AUTO_INCREMENT"
-			}
+// createTableMySQL renders a CREATE TABLE statement with every constraint and
+// index declared inline
+func createTableMySQL(table model.Table) string {
+	var columns []string
+	var primaryKeys []string
 
-			if col.Unique && !col.PrimaryKey {
-				colDef += " UNIQUE"
-			}
+	for _, col := range table.Columns {
+		columns = append(columns, "  "+columnDefMySQL(col))
 
-			if col.Default != nil {
-				colDef += fmt.Sprintf(" DEFAULT %s", formatDefaultMySQL(*col.Default, col.Type))
-			}
+		if col.PrimaryKey {
+			primaryKeys = append(primaryKeys, quoteIdentMySQL(col.Name))
+		}
+	}
 
-			if len(col.EnumValues) > 0 && strings.ToUpper(col.Type) == "ENUM" {
-				enumVals := make([]string, len(col.EnumValues))
-				for i, v := range col.EnumValues {
-					enumVals[i] = fmt.Sprintf("'%s'", v)
-				}
-				colDef = fmt.Sprintf("  `%s` ENUM(%s)", col.Name, strings.Join(enumVals, ", "))
-				if col.NotNull {
-					colDef += " NOT NULL"
-				}
-			}
+	if len(primaryKeys) > 0 {
+		columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	}
 
-			columns = append(columns, colDef)
+	for _, u := range table.Uniques {
+		columns = append(columns, "  "+uniqueClause(u, quoteIdentMySQL))
+	}
 
-			if col.PrimaryKey {
-				primaryKeys = append(primaryKeys, fmt.Sprintf("`%s`", col.Name))
-			}
-		}
+	for _, ch := range table.Checks {
+		columns = append(columns, "  "+checkClause(ch, quoteIdentMySQL))
+	}
 
-		if len(primaryKeys) > 0 {
-			columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
-		}
+	for _, idx := range table.Indexes {
+		columns = append(columns, "  "+indexMySQL(table.Name, idx))
+	}
 
-		for _, fk := range table.ForeignKeys {
-			fkDef := fmt.Sprintf("  FOREIGN KEY (`%s`) REFERENCES `%s`(`%s`)",
-				fk.Column, fk.References.Table, fk.References.Column)
-			if fk.OnDelete != "" {
-				fkDef += fmt.Sprintf(" ON DELETE %s", fk.OnDelete)
-			}
-			if fk.OnUpdate != "" {
-				fkDef += fmt.Sprintf(" ON UPDATE %s", fk.OnUpdate)
-			}
-			columns = append(columns, fkDef)
-		}
+	// Unnamed keys get the name migrations drop them by, rather than the
+	// <table>_ibfk_<n> MySQL would pick
+	for _, fk := range table.ForeignKeys {
+		columns = append(columns, "  "+foreignKeyMySQL(namedForeignKey(table.Name, fk)))
+	}
 
-		sb.WriteString(strings.Join(columns, ",\n"))
+	engine := "InnoDB"
+	if isMySQLEngineName(table.Engine) {
+		engine = table.Engine
+	}
+	options := fmt.Sprintf("ENGINE=%s DEFAULT CHARSET=utf8mb4", engine)
+	if table.Comment != "" {
+		options += " COMMENT=" + quoteStringMySQL(table.Comment)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n) %s;\n", quoteIdentMySQL(table.Name), strings.Join(columns, ",\n"), options)
+}
 
-		engine := "InnoDB"
-		if table.Engine != "" {
-			engine = table.Engine
+// columnDefMySQL renders a column definition as used by CREATE TABLE and
+// ALTER TABLE
+func columnDefMySQL(col model.Column) string {
+	mysqlType := sizedType(mapTypeToMySQL(col.Type), col, 255)
+	if col.Unsigned && isNumericType(col.Type) {
+		mysqlType += " UNSIGNED"
+	}
+	if isEnumColumn(col) {
+		enumVals := make([]string, len(col.EnumValues))
+		for i, v := range col.EnumValues {
+			enumVals[i] = quoteStringMySQL(v)
 		}
-		sb.WriteString(fmt.Sprintf("\n) ENGINE=%s DEFAULT CHARSET=utf8mb4;\n\n", engine))
+		mysqlType = fmt.Sprintf("ENUM(%s)", strings.Join(enumVals, ", "))
 	}
 
-	return sb.String(), nil
+	colDef := fmt.Sprintf("%s %s", quoteIdentMySQL(col.Name), mysqlType)
+
+	if col.NotNull {
+		colDef += " NOT NULL"
+	}
+
+	if col.AutoIncrement {
+		colDef += " AUTO_INCREMENT"
+	}
+
+	if col.Unique && !col.PrimaryKey {
+		colDef += " UNIQUE"
+	}
+
+	if col.Default != nil {
+		colDef += fmt.Sprintf(" DEFAULT %s", formatDefaultMySQL(*col.Default, col.Type))
+	}
+
+	if col.Comment != "" {
+		colDef += " COMMENT " + quoteStringMySQL(col.Comment)
+	}
+
+	return colDef
 }
 
 func exportPostgres(schema model.SchemaData) (string, error) {
@@ -114,80 +190,141 @@ func exportPostgres(schema model.SchemaData) (string, error) {
 	// First, create ENUM types if needed
 	for _, table := range schema.Tables {
 		for _, col := range table.Columns {
-			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
-				enumName := fmt.Sprintf("%s_%s_enum", table.Name, col.Name)
-				enumVals := make([]string, len(col.EnumValues))
-				for i, v := range col.EnumValues {
-					enumVals[i] = fmt.Sprintf("'%s'", v)
-				}
-				sb.WriteString(fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);\n\n", enumName, strings.Join(enumVals, ", ")))
+			if isEnumColumn(col) {
+				sb.WriteString(createEnumPostgres(table.Name, col) + "\n\n")
 			}
 		}
 	}
 
-	for _, table := range schema.Tables {
-		sb.WriteString(fmt.Sprintf("CREATE TABLE \"%s\" (\n", table.Name))
+	tables, deferred := orderTables(schema.Tables)
 
-		var columns []string
-		var primaryKeys []string
+	for _, table := range tables {
+		sb.WriteString(createTablePostgres(table) + "\n")
+	}
 
-		for _, col := range table.Columns {
-			pgType := mapTypeToPostgres(col.Type)
+	// Foreign keys in cycles are added once both ends exist
+	for _, d := range deferred {
+		sb.WriteString(fmt.Sprintf("ALTER TABLE %s ADD %s;\n", quoteIdentANSI(d.Table), foreignKeyANSI(d.ForeignKey)))
+	}
 
-			if col.AutoIncrement {
-				if strings.Contains(strings.ToUpper(col.Type), "BIG") {
-					pgType = "BIGSERIAL"
-				} else {
-					pgType = "SERIAL"
-				}
-			}
+	return sb.String(), nil
+}
 
-			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
-				pgType = fmt.Sprintf("%s_%s_enum", table.Name, col.Name)
-			}
+// createTablePostgres renders a CREATE TABLE statement followed by the
+// table's indexes and comments, one statement per line
+func createTablePostgres(table model.Table) string {
+	var columns []string
+	var primaryKeys []string
 
-			colDef := fmt.Sprintf("  \"%s\" %s", col.Name, pgType)
+	for _, col := range table.Columns {
+		columns = append(columns, "  "+columnDefPostgres(table.Name, col))
 
-			if col.NotNull && !col.AutoIncrement {
-				colDef += " NOT NULL"
-			}
+		if col.PrimaryKey {
+			primaryKeys = append(primaryKeys, quoteIdentANSI(col.Name))
+		}
+	}
 
-			if col.Unique && !col.PrimaryKey {
-				colDef += " UNIQUE"
-			}
+	if len(primaryKeys) > 0 {
+		columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	}
 
-			if col.Default != nil && !col.AutoIncrement {
-				colDef += fmt.Sprintf(" DEFAULT %s", formatDefaultPostgres(*col.Default, col.Type))
-			}
+	for _, u := range table.Uniques {
+		columns = append(columns, "  "+uniqueClause(u, quoteIdentANSI))
+	}
 
-			columns = append(columns, colDef)
+	for _, ch := range table.Checks {
+		columns = append(columns, "  "+checkClause(ch, quoteIdentANSI))
+	}
 
-			if col.PrimaryKey {
-				primaryKeys = append(primaryKeys, fmt.Sprintf("\"%s\"", col.Name))
-			}
-		}
+	for _, fk := range table.ForeignKeys {
+		columns = append(columns, "  "+foreignKeyANSI(namedForeignKey(table.Name, fk)))
+	}
 
-		if len(primaryKeys) > 0 {
-			columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n%s\n);\n", quoteIdentANSI(table.Name), strings.Join(columns, ",\n")))
+
+	for _, idx := range table.Indexes {
+		sb.WriteString(createIndexPostgres(table.Name, idx) + "\n")
+	}
+
+	if table.Comment != "" {
+		sb.WriteString(commentOnTablePostgres(table.Name, table.Comment) + "\n")
+	}
+	for _, col := range table.Columns {
+		if col.Comment != "" {
+			sb.WriteString(commentOnColumnPostgres(table.Name, col.Name, col.Comment) + "\n")
 		}
+	}
+	return sb.String()
+}
 
-		for _, fk := range table.ForeignKeys {
-			fkDef := fmt.Sprintf("  FOREIGN KEY (\"%s\") REFERENCES \"%s\"(\"%s\")",
-				fk.Column, fk.References.Table, fk.References.Column)
-			if fk.OnDelete != "" {
-				fkDef += fmt.Sprintf(" ON DELETE %s", fk.OnDelete)
-			}
-			if fk.OnUpdate != "" {
-				fkDef += fmt.Sprintf(" ON UPDATE %s", fk.OnUpdate)
-			}
-			columns = append(columns, fkDef)
+// columnDefPostgres renders a column definition as used by CREATE TABLE and
+// ALTER TABLE ADD COLUMN. Auto-incrementing columns become serials.
+func columnDefPostgres(table string, col model.Column) string {
+	pgType := typePostgres(table, col)
+
+	if col.AutoIncrement {
+		if strings.Contains(strings.ToUpper(col.Type), "BIG") {
+			pgType = "BIGSERIAL"
+		} else {
+			pgType = "SERIAL"
 		}
+	}
+
+	colDef := fmt.Sprintf("%s %s", quoteIdentANSI(col.Name), pgType)
 
-		sb.WriteString(strings.Join(columns, ",\n"))
-		sb.WriteString("\n);\n\n")
+	if col.NotNull && !col.AutoIncrement {
+		colDef += " NOT NULL"
 	}
 
-	return sb.String(), nil
+	if col.Unique && !col.PrimaryKey {
+		colDef += " UNIQUE"
+	}
+
+	if col.Default != nil && !col.AutoIncrement {
+		colDef += fmt.Sprintf(" DEFAULT %s", formatDefaultPostgres(*col.Default, col.Type))
+	}
+
+	return colDef
+}
+
+// typePostgres returns the column's type, which for enums is the type created
+// for the column
+func typePostgres(table string, col model.Column) string {
+	if isEnumColumn(col) {
+		return enumTypePostgres(table, col.Name)
+	}
+	return sizedType(mapTypeToPostgres(col.Type), col, 0)
+}
+
+// enumTypePostgres returns the quoted name of the type created for an enum
+// column
+func enumTypePostgres(table, column string) string {
+	return quoteIdentANSI(fmt.Sprintf("%s_%s_enum", table, column))
+}
+
+func createEnumPostgres(table string, col model.Column) string {
+	enumVals := make([]string, len(col.EnumValues))
+	for i, v := range col.EnumValues {
+		enumVals[i] = quoteStringANSI(v)
+	}
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", enumTypePostgres(table, col.Name), strings.Join(enumVals, ", "))
+}
+
+func commentOnTablePostgres(table, comment string) string {
+	return fmt.Sprintf("COMMENT ON TABLE %s IS %s;", quoteIdentANSI(table), commentValuePostgres(comment))
+}
+
+func commentOnColumnPostgres(table, column, comment string) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", quoteIdentANSI(table), quoteIdentANSI(column), commentValuePostgres(comment))
+}
+
+// commentValuePostgres quotes a comment, where an empty one removes it
+func commentValuePostgres(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return quoteStringANSI(comment)
 }
 
 func exportMongo(schema model.SchemaData) (string, error) {
@@ -197,15 +334,28 @@ func exportMongo(schema model.SchemaData) (string, error) {
 	sb.WriteString("// Generated by DB Schema Generator\n\n")
 
 	for _, table := range schema.Tables {
-		sb.WriteString(fmt.Sprintf("db.createCollection(\"%s\", {\n", table.Name))
+		// CHECK constraints become allOf rules where $jsonSchema can express them
+		var rules []string
+		for _, ch := range table.Checks {
+			if r, ok := mongoCheckRules(ch.Expression); ok {
+				rules = append(rules, r...)
+			} else {
+				sb.WriteString(fmt.Sprintf("// CHECK %s has no $jsonSchema equivalent and was left out\n", singleLine(checkLabel(ch))))
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("db.createCollection(%s, {\n", quoteJS(table.Name)))
 		sb.WriteString("  validator: {\n")
 		sb.WriteString("    $jsonSchema: {\n")
 		sb.WriteString("      bsonType: \"object\",\n")
+		if table.Comment != "" {
+			sb.WriteString(fmt.Sprintf("      description: %s,\n", quoteJS(table.Comment)))
+		}
 
 		var required []string
 		for _, col := range table.Columns {
 			if col.NotNull || col.PrimaryKey {
-				required = append(required, fmt.Sprintf("\"%s\"", col.Name))
+				required = append(required, quoteJS(col.Name))
 			}
 		}
 
@@ -217,38 +367,80 @@ func exportMongo(schema model.SchemaData) (string, error) {
 
 		var props []string
 		for _, col := range table.Columns {
-			prop := fmt.Sprintf("        \"%s\": {\n", col.Name)
-			prop += fmt.Sprintf("          bsonType: \"%s\"", mapTypeToMongo(col.Type))
+			prop := fmt.Sprintf("        %s: {\n", quoteJS(col.Name))
+			bsonType := mapTypeToMongo(col.Type)
+			prop += fmt.Sprintf("          bsonType: \"%s\"", bsonType)
+
+			if bsonType == "string" && col.Length > 0 && len(col.EnumValues) == 0 {
+				prop += fmt.Sprintf(",\n          maxLength: %d", col.Length)
+			}
+			if col.Unsigned && isNumericType(col.Type) {
+				prop += ",\n          minimum: 0"
+			}
 
 			if len(col.EnumValues) > 0 {
 				enumVals := make([]string, len(col.EnumValues))
 				for i, v := range col.EnumValues {
-					enumVals[i] = fmt.Sprintf("\"%s\"", v)
+					enumVals[i] = quoteJS(v)
 				}
 				prop += fmt.Sprintf(",\n          enum: [%s]", strings.Join(enumVals, ", "))
 			}
 
+			if col.Comment != "" {
+				prop += fmt.Sprintf(",\n          description: %s", quoteJS(col.Comment))
+			}
+
 			prop += "\n        }"
 			props = append(props, prop)
 		}
 
 		sb.WriteString(strings.Join(props, ",\n"))
-		sb.WriteString("\n      }\n")
+		if len(rules) > 0 {
+			sb.WriteString("\n      },\n")
+			sb.WriteString("      allOf: [\n")
+			sb.WriteString("        " + strings.Join(rules, ",\n        "))
+			sb.WriteString("\n      ]\n")
+		} else {
+			sb.WriteString("\n      }\n")
+		}
 		sb.WriteString("    }\n")
 		sb.WriteString("  }\n")
 		sb.WriteString("});\n\n")
 
-		// Create indexes for foreign keys
+		// Create indexes for foreign keys, compound for composite keys, unless
+		// another index already starts with the same keys
+		indexed := mongoIndexedKeys(table)
 		for _, fk := range table.ForeignKeys {
-			sb.WriteString(fmt.Sprintf("db.%s.createIndex({ \"%s\": 1 });\n", table.Name, fk.Column))
+			if hasKeyPrefix(indexed, fk.SourceColumns()) {
+				continue
+			}
+			indexed = append(indexed, fk.SourceColumns())
+			sb.WriteString(fmt.Sprintf("%s.createIndex(%s);\n", mongoCollection(table.Name), mongoIndexKeys(fk.SourceColumns())))
 		}
 
-		// Create unique indexes
+		// Create unique indexes, one compound index for a composite primary key
+		var primaryKeys []string
 		for _, col := range table.Columns {
-			if col.Unique || col.PrimaryKey {
-				sb.WriteString(fmt.Sprintf("db.%s.createIndex({ \"%s\": 1 }, { unique: true });\n", table.Name, col.Name))
+			if col.PrimaryKey {
+				primaryKeys = append(primaryKeys, col.Name)
 			}
 		}
+		if len(primaryKeys) > 0 {
+			sb.WriteString(fmt.Sprintf("%s.createIndex(%s, { unique: true });\n", mongoCollection(table.Name), mongoIndexKeys(primaryKeys)))
+		}
+		for _, col := range table.Columns {
+			if col.Unique && !col.PrimaryKey {
+				sb.WriteString(fmt.Sprintf("%s.createIndex(%s, { unique: true });\n", mongoCollection(table.Name), mongoIndexKeys([]string{col.Name})))
+			}
+		}
+
+		for _, u := range table.Uniques {
+			sb.WriteString(createUniqueMongo(table.Name, u) + "\n")
+		}
+
+		for _, idx := range table.Indexes {
+			sb.WriteString(createIndexMongo(table.Name, idx) + "\n")
+		}
 
 		sb.WriteString("\n")
 	}
@@ -256,6 +448,154 @@ func exportMongo(schema model.SchemaData) (string, error) {
 	return sb.String(), nil
 }
 
+// foreignKeyMySQL renders a foreign key clause for MySQL, which parses MATCH
+// but has no deferred constraint checking
+func foreignKeyMySQL(fk model.ForeignKey) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+		quoteList(fk.SourceColumns(), quoteIdentMySQL), quoteIdentMySQL(fk.References.Table),
+		quoteList(fk.TargetColumns(), quoteIdentMySQL))
+	if fk.Name != "" {
+		def = fmt.Sprintf("CONSTRAINT %s %s", quoteIdentMySQL(fk.Name), def)
+	}
+	if match := matchType(fk.Match); match != "" {
+		def += " MATCH " + match
+	}
+	if action := referentialAction(fk.OnDelete); action != "" {
+		def += fmt.Sprintf(" ON DELETE %s", action)
+	}
+	if action := referentialAction(fk.OnUpdate); action != "" {
+		def += fmt.Sprintf(" ON UPDATE %s", action)
+	}
+	return def
+}
+
+// foreignKeyANSI renders a foreign key clause for PostgreSQL and SQLite
+func foreignKeyANSI(fk model.ForeignKey) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+		quoteList(fk.SourceColumns(), quoteIdentANSI), quoteIdentANSI(fk.References.Table),
+		quoteList(fk.TargetColumns(), quoteIdentANSI))
+	if fk.Name != "" {
+		def = fmt.Sprintf("CONSTRAINT %s %s", quoteIdentANSI(fk.Name), def)
+	}
+	// PostgreSQL rejects MATCH PARTIAL as not implemented
+	if match := matchType(fk.Match); match != "" && match != "PARTIAL" {
+		def += " MATCH " + match
+	}
+	if action := referentialAction(fk.OnDelete); action != "" {
+		def += fmt.Sprintf(" ON DELETE %s", action)
+	}
+	if action := referentialAction(fk.OnUpdate); action != "" {
+		def += fmt.Sprintf(" ON UPDATE %s", action)
+	}
+	if fk.Deferrable {
+		def += " DEFERRABLE"
+		if fk.InitiallyDeferred {
+			def += " INITIALLY DEFERRED"
+		}
+	}
+	return def
+}
+
+// quoteList quotes each name and joins them for a column list
+func quoteList(names []string, quote func(string) string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// matchType normalizes a MATCH option, dropping unknown ones
+func matchType(match string) string {
+	upper := strings.ToUpper(strings.TrimSpace(match))
+	switch upper {
+	case "FULL", "PARTIAL", "SIMPLE":
+		return upper
+	default:
+		return ""
+	}
+}
+
+// mongoIndexedKeys lists the key columns of the ascending indexes created
+// for the table's primary key, unique constraints and indexes. MongoDB
+// rejects a second index on the same keys, and one on a prefix of them is
+// redundant.
+func mongoIndexedKeys(table model.Table) [][]string {
+	var indexed [][]string
+	var primaryKeys []string
+	for _, col := range table.Columns {
+		if col.PrimaryKey {
+			primaryKeys = append(primaryKeys, col.Name)
+		} else if col.Unique {
+			indexed = append(indexed, []string{col.Name})
+		}
+	}
+	if len(primaryKeys) > 0 {
+		indexed = append(indexed, primaryKeys)
+	}
+	for _, u := range table.Uniques {
+		indexed = append(indexed, u.Columns)
+	}
+	for _, idx := range table.Indexes {
+		if mongoIndexKind(idx.Method) != "" && !idx.Unique {
+			continue
+		}
+		ascending := true
+		for _, col := range idx.Columns {
+			ascending = ascending && !col.IsDescending()
+		}
+		if ascending {
+			indexed = append(indexed, idx.ColumnNames())
+		}
+	}
+	return indexed
+}
+
+// hasKeyPrefix reports whether one of the key lists starts with columns
+func hasKeyPrefix(indexed [][]string, columns []string) bool {
+	for _, keys := range indexed {
+		if len(keys) >= len(columns) && slices.Equal(keys[:len(columns)], columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// mongoIndexKeys renders the key document of an ascending index
+func mongoIndexKeys(columns []string) string {
+	keys := make([]string, len(columns))
+	for i, col := range columns {
+		keys[i] = quoteJS(col) + ": 1"
+	}
+	return "{ " + strings.Join(keys, ", ") + " }"
+}
+
+// referentialAction normalizes an ON DELETE/ON UPDATE action, dropping
+// anything that is not one of the standard actions
+func referentialAction(action string) string {
+	upper := strings.ToUpper(strings.Join(strings.Fields(action), " "))
+	switch upper {
+	case "CASCADE", "RESTRICT", "SET NULL", "SET DEFAULT", "NO ACTION":
+		return upper
+	default:
+		return ""
+	}
+}
+
+// isMySQLEngineName reports whether a storage engine name is safe to write
+// unquoted after ENGINE=
+func isMySQLEngineName(engine string) bool {
+	if engine == "" {
+		return false
+	}
+	for _, r := range engine {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
 func mapTypeToMySQL(t string) string {
 	upper := strings.ToUpper(t)
 	switch upper {
@@ -331,30 +671,59 @@ func formatDefaultMySQL(def string, colType string) string {
 	if upper == "NULL" || upper == "CURRENT_TIMESTAMP" || upper == "NOW()" {
 		return upper
 	}
+	if upper == "UUID()" {
+		// Function defaults other than the current time need parentheses
+		return "(UUID())"
+	}
 	if upper == "TRUE" {
 		return "1"
 	}
 	if upper == "FALSE" {
 		return "0"
 	}
-	// Check if numeric
-	if _, err := fmt.Sscanf(def, "%f", new(float64)); err == nil {
+	if isNumericLiteral(def) {
 		return def
 	}
-	return fmt.Sprintf("'%s'", def)
+	return quoteStringMySQL(def)
 }
 
 func formatDefaultPostgres(def string, colType string) string {
 	upper := strings.ToUpper(def)
-	if upper == "NULL" || upper == "NOW()" || upper == "CURRENT_TIMESTAMP" {
+	switch upper {
+	case "NULL", "NOW()", "CURRENT_TIMESTAMP", "CURRENT_DATE", "CURRENT_TIME", "LOCALTIMESTAMP",
+		"GEN_RANDOM_UUID()", "UUID_GENERATE_V4()":
 		return upper
 	}
 	if upper == "TRUE" || upper == "FALSE" {
 		return upper
 	}
-	// Check if numeric
-	if _, err := fmt.Sscanf(def, "%f", new(float64)); err == nil {
+	if isNumericLiteral(def) {
 		return def
 	}
-	return fmt.Sprintf("'%s'", def)
+	return quoteStringANSI(def)
+}
+
+// isEnumColumn reports whether the column is an enum with values to declare
+func isEnumColumn(col model.Column) bool {
+	return strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0
+}
+
+// findTableColumn returns a pointer to the named column, or nil when the table
+// has no such column
+func findTableColumn(table model.Table, name string) *model.Column {
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			return &table.Columns[i]
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,60 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestExportMongoSkipsCoveredForeignKeyIndexes(t *testing.T) {
+	schema := model.SchemaData{Tables: []model.Table{
+		{Name: "orders", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+		{Name: "products", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+		{Name: "shipments", Columns: []model.Column{
+			{Name: "order_id", Type: "INT", PrimaryKey: true},
+			{Name: "line_no", Type: "INT", PrimaryKey: true},
+		}},
+		{
+			Name: "order_items",
+			Columns: []model.Column{
+				{Name: "order_id", Type: "INT", PrimaryKey: true},
+				{Name: "line_no", Type: "INT", PrimaryKey: true},
+				{Name: "product_id", Type: "INT"},
+				{Name: "sku", Type: "VARCHAR", Length: 32},
+			},
+			ForeignKeys: []model.ForeignKey{
+				// The composite key is also the primary key
+				{Columns: []string{"order_id", "line_no"}, References: model.Reference{Table: "shipments", Columns: []string{"order_id", "line_no"}}},
+				// A prefix of the primary key
+				{Column: "order_id", References: model.Reference{Table: "orders", Column: "id"}},
+				// Covered by an index
+				{Column: "sku", References: model.Reference{Table: "products", Column: "id"}},
+				// Not covered, and listed twice
+				{Column: "product_id", References: model.Reference{Table: "products", Column: "id"}},
+				{Column: "product_id", References: model.Reference{Table: "products", Column: "id"}},
+			},
+			Indexes: []model.Index{{Name: "items_sku", Columns: []model.IndexColumn{{Name: "sku"}, {Name: "line_no"}}}},
+		},
+	}}
+
+	out, err := Export(schema, FormatMongo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`db.order_items.createIndex({ "product_id": 1 });`,
+		`db.order_items.createIndex({ "order_id": 1, "line_no": 1 }, { unique: true });`,
+		`db.order_items.createIndex({ "sku": 1, "line_no": 1 }, { name: "items_sku" });`,
+	}
+	var got []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "db.order_items.createIndex(") {
+			got = append(got, line)
+		}
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("order_items indexes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,296 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// goInitialisms are words rendered in all caps in Go identifiers, following
+// the conventions used by golint
+var goInitialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true, "dns": true,
+	"eof": true, "guid": true, "html": true, "http": true, "https": true, "id": true,
+	"ip": true, "json": true, "lhs": true, "qps": true, "ram": true, "rhs": true,
+	"rpc": true, "sla": true, "smtp": true, "sql": true, "ssh": true, "tcp": true,
+	"tls": true, "ttl": true, "udp": true, "ui": true, "uid": true, "uri": true,
+	"url": true, "utf8": true, "uuid": true, "vm": true, "xml": true,
+}
+
+type goField struct {
+	name    string
+	typ     string
+	tag     string
+	comment string
+}
+
+type goEnum struct {
+	name   string
+	values []string
+}
+
+func exportGo(schema model.SchemaData, opts Options) (string, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "models"
+	}
+	if !token.IsIdentifier(pkg) || pkg == "_" {
+		return "", errors.New("go package must be a valid identifier")
+	}
+
+	tags := opts.Tags
+	if len(tags) == 0 {
+		tags = []string{"gorm", "json"}
+	}
+	for _, t := range tags {
+		if t != "gorm" && t != "db" && t != "json" {
+			return "", fmt.Errorf("unsupported go tag style: %s", t)
+		}
+	}
+	useGorm := false
+	for _, t := range tags {
+		if t == "gorm" {
+			useGorm = true
+		}
+	}
+
+	imports := make(map[string]bool)
+	var enums []goEnum
+	var body strings.Builder
+
+	// Structs, enum types and enum constants share the package scope. Structs
+	// are named first so that table names win over derived enum names.
+	names := make(map[string]bool)
+	structNames := make([]string, len(schema.Tables))
+	for i, table := range schema.Tables {
+		structNames[i] = uniqueGoName(names, goIdentifier(table.Name))
+	}
+
+	for ti, table := range schema.Tables {
+		structName := structNames[ti]
+		used := make(map[string]bool)
+		if useGorm {
+			// A field cannot share the name of the TableName method
+			used["TableName"] = true
+		}
+
+		var fields []goField
+		for _, col := range table.Columns {
+			name := uniqueGoName(used, goIdentifier(col.Name))
+
+			nullable := !col.NotNull && !col.PrimaryKey
+
+			var typ string
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				enumName := uniqueGoName(names, structName+goIdentifier(col.Name))
+				enums = append(enums, goEnum{name: enumName, values: col.EnumValues})
+				typ = enumName
+				if nullable {
+					if opts.NullTypes {
+						typ = "sql.NullString"
+						imports["database/sql"] = true
+					} else {
+						typ = "*" + typ
+					}
+				}
+			} else {
+				typ = mapTypeToGo(col.Type, nullable, opts.NullTypes, imports)
+			}
+
+			fields = append(fields, goField{
+				name:    name,
+				typ:     typ,
+				tag:     goStructTag(col, tags),
+				comment: col.Comment,
+			})
+		}
+
+		body.WriteString(fmt.Sprintf("// %s maps the %s table\n", structName, strconv.Quote(table.Name)))
+		if table.Comment != "" {
+			body.WriteString("//\n" + lineComment(table.Comment, "", "//"))
+		}
+		body.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+		for _, f := range fields {
+			if f.comment != "" {
+				body.WriteString(lineComment(f.comment, "\t", "//"))
+			}
+			body.WriteString(fmt.Sprintf("\t%s %s %s\n", f.name, f.typ, f.tag))
+		}
+		body.WriteString("}\n\n")
+
+		if useGorm {
+			body.WriteString(fmt.Sprintf("func (%s) TableName() string {\n\treturn %s\n}\n\n", structName, strconv.Quote(table.Name)))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Go Schema Export\n")
+	sb.WriteString("// Generated by DB Schema Generator\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+
+	if len(imports) > 0 {
+		var paths []string
+		for p := range imports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+
+		sb.WriteString("import (\n")
+		for _, p := range paths {
+			sb.WriteString(fmt.Sprintf("\t%s\n", strconv.Quote(p)))
+		}
+		sb.WriteString(")\n\n")
+	}
+
+	for _, e := range enums {
+		sb.WriteString(fmt.Sprintf("type %s string\n\n", e.name))
+		sb.WriteString("const (\n")
+		for _, v := range e.values {
+			constName := uniqueGoName(names, e.name+goIdentifier(v))
+			sb.WriteString(fmt.Sprintf("\t%s %s = %s\n", constName, e.name, strconv.Quote(v)))
+		}
+		sb.WriteString(")\n\n")
+	}
+
+	sb.WriteString(body.String())
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format go source: %w", err)
+	}
+	return string(src), nil
+}
+
+func goStructTag(col model.Column, tags []string) string {
+	var parts []string
+	for _, t := range tags {
+		switch t {
+		case "gorm":
+			opts := []string{"column:" + col.Name}
+			if col.PrimaryKey {
+				opts = append(opts, "primaryKey")
+			}
+			if col.AutoIncrement {
+				opts = append(opts, "autoIncrement")
+			}
+			if col.NotNull && !col.PrimaryKey {
+				opts = append(opts, "not null")
+			}
+			if col.Unique && !col.PrimaryKey {
+				opts = append(opts, "unique")
+			}
+			switch familyOf(col.Type) {
+			case familyVarString, familyFixedString:
+				if col.Length > 0 {
+					opts = append(opts, fmt.Sprintf("size:%d", col.Length))
+				}
+			case familyDecimal, familyTemporal:
+				if col.Precision > 0 {
+					opts = append(opts, fmt.Sprintf("precision:%d", col.Precision))
+				}
+				if col.Scale > 0 {
+					opts = append(opts, fmt.Sprintf("scale:%d", col.Scale))
+				}
+			}
+			if col.Default != nil {
+				opts = append(opts, "default:"+*col.Default)
+			}
+			parts = append(parts, "gorm:"+strconv.Quote(strings.Join(opts, ";")))
+		case "db":
+			parts = append(parts, "db:"+strconv.Quote(col.Name))
+		case "json":
+			parts = append(parts, "json:"+strconv.Quote(col.Name))
+		}
+	}
+
+	tag := strings.Join(parts, " ")
+	// A raw string literal cannot hold a backtick, so fall back to an interpreted one
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+func mapTypeToGo(t string, nullable bool, nullTypes bool, imports map[string]bool) string {
+	upper := strings.ToUpper(t)
+
+	var base, null string
+	switch {
+	case upper == "BIGINT" || upper == "BIGSERIAL":
+		base, null = "int64", "sql.NullInt64"
+	case upper == "SMALLINT":
+		base, null = "int16", "sql.NullInt16"
+	case upper == "TINYINT(1)" || strings.Contains(upper, "BOOL"):
+		base, null = "bool", "sql.NullBool"
+	case upper == "TINYINT":
+		base, null = "int8", "sql.NullInt16"
+	case strings.Contains(upper, "INT") || upper == "SERIAL" || upper == "YEAR":
+		base, null = "int", "sql.NullInt64"
+	case strings.Contains(upper, "FLOAT") || strings.Contains(upper, "DOUBLE") || upper == "REAL":
+		base, null = "float64", "sql.NullFloat64"
+	case strings.Contains(upper, "DECIMAL") || strings.Contains(upper, "NUMERIC"):
+		// Kept as a string so no precision is lost
+		base, null = "string", "sql.NullString"
+	case upper == "DATE" || upper == "TIME" || strings.Contains(upper, "TIMESTAMP") || upper == "DATETIME":
+		base, null = "time.Time", "sql.NullTime"
+		if !(nullable && nullTypes) {
+			imports["time"] = true
+		}
+	case upper == "JSON" || upper == "JSONB":
+		// A nil RawMessage already represents NULL
+		imports["encoding/json"] = true
+		return "json.RawMessage"
+	case strings.Contains(upper, "BLOB") || strings.Contains(upper, "BINARY") || upper == "BYTEA":
+		return "[]byte"
+	default:
+		base, null = "string", "sql.NullString"
+	}
+
+	if !nullable {
+		return base
+	}
+	if nullTypes {
+		imports["database/sql"] = true
+		return null
+	}
+	return "*" + base
+}
+
+// uniqueGoName returns name, numbered if it is already in used, and adds the
+// result to used
+func uniqueGoName(used map[string]bool, name string) string {
+	base := name
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+// goIdentifier converts a table, column or enum value name into an exported
+// Go identifier, e.g. "user_id" -> "UserID"
+func goIdentifier(s string) string {
+	var sb strings.Builder
+	for _, w := range splitWords(s) {
+		if goInitialisms[w] {
+			sb.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+
+	ident := sanitizeIdentifier(sb.String())
+	if !unicode.IsUpper(rune(ident[0])) {
+		ident = "X" + strings.TrimLeft(ident, "_")
+	}
+	return ident
+}
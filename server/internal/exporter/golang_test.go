@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// typeCheckGo fails the test unless src is a Go file that compiles
+func typeCheckGo(t *testing.T, src string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "models.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check(f.Name.Name, fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, src)
+	}
+}
+
+func TestExportGoNamesDoNotCollide(t *testing.T) {
+	schema := model.SchemaData{Tables: []model.Table{
+		{Name: "user", Columns: []model.Column{
+			{Name: "id", Type: "INT", PrimaryKey: true},
+			{Name: "role", Type: "ENUM", EnumValues: []string{"admin", "member"}},
+			{Name: "table_name", Type: "TEXT"},
+			{Name: "created_at", Type: "TIMESTAMP"},
+		}},
+		// Named like the enum type and one of its constants above
+		{Name: "user_role", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+		{Name: "user_role_admin", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+	}}
+
+	for _, opts := range []Options{{}, {Tags: []string{"db"}, NullTypes: true}} {
+		src, err := ExportWithOptions(schema, FormatGo, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		typeCheckGo(t, src)
+	}
+}
+
+func TestExportGoRejectsInvalidPackage(t *testing.T) {
+	schema := model.SchemaData{Tables: []model.Table{
+		{Name: "users", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+	}}
+
+	for _, pkg := range []string{"func", "_", "my-models", "models\n\nfunc init() {}", "1models"} {
+		if _, err := ExportWithOptions(schema, FormatGo, Options{Package: pkg}); err == nil {
+			t.Errorf("package %q was accepted", pkg)
+		}
+	}
+
+	src, err := ExportWithOptions(schema, FormatGo, Options{Package: "dbmodels"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, "package dbmodels\n") {
+		t.Errorf("package clause missing:\n%s", src)
+	}
+	typeCheckGo(t, src)
+}
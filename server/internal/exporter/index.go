@@ -0,0 +1,176 @@
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+var indexMethodRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// indexName returns the index's own name or one derived from its table and
+// columns, since SQL index names have to be unique
+func indexName(table string, idx model.Index) string {
+	if idx.Name != "" {
+		return idx.Name
+	}
+	prefix := "idx"
+	if idx.Unique {
+		prefix = "uq"
+	}
+	return fmt.Sprintf("%s_%s_%s", prefix, table, strings.Join(idx.ColumnNames(), "_"))
+}
+
+// indexColumnList renders the indexed columns with their sort order
+func indexColumnList(idx model.Index, quote func(string) string) string {
+	cols := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		cols[i] = quote(col.Name)
+		if col.IsDescending() {
+			cols[i] += " DESC"
+		}
+	}
+	return strings.Join(cols, ", ")
+}
+
+// indexMethod returns the lowercased access method, or "" when it is not a
+// plain identifier that can be written unquoted
+func indexMethod(idx model.Index) string {
+	if !indexMethodRe.MatchString(idx.Method) {
+		return ""
+	}
+	return strings.ToLower(idx.Method)
+}
+
+// createIndexPostgres renders a CREATE INDEX statement with every option
+// Postgres supports
+func createIndexPostgres(table string, idx model.Index) string {
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	stmt += fmt.Sprintf("INDEX %s ON %s", quoteIdentANSI(indexName(table, idx)), quoteIdentANSI(table))
+	switch method := indexMethod(idx); {
+	case method == "", method == "fulltext", method == "clustered", method == "nonclustered":
+		// Kinds from other databases with no Postgres access method
+	case idx.Unique && method != "btree":
+		// Only btree supports unique indexes, and it is the default
+	case method == "spatial":
+		stmt += " USING gist"
+	default:
+		stmt += " USING " + method
+	}
+	stmt += fmt.Sprintf(" (%s)", indexColumnList(idx, quoteIdentANSI))
+	if len(idx.Include) > 0 {
+		stmt += fmt.Sprintf(" INCLUDE (%s)", quoteList(idx.Include, quoteIdentANSI))
+	}
+	if where := strings.TrimSpace(idx.Where); where != "" {
+		stmt += " WHERE " + where
+	}
+	return stmt + ";"
+}
+
+// createIndexSQLite renders a CREATE INDEX statement. SQLite supports partial
+// indexes but has no access methods or INCLUDE columns.
+func createIndexSQLite(table string, idx model.Index) string {
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	stmt += fmt.Sprintf("INDEX %s ON %s (%s)", quoteIdentANSI(indexName(table, idx)), quoteIdentANSI(table), indexColumnList(idx, quoteIdentANSI))
+	if where := strings.TrimSpace(idx.Where); where != "" {
+		stmt += " WHERE " + where
+	}
+	return stmt + ";"
+}
+
+// createIndexMSSQL renders a CREATE INDEX statement. The clustered and
+// nonclustered methods are the only ones SQL Server knows; a WHERE predicate
+// makes a filtered index.
+func createIndexMSSQL(table string, idx model.Index) string {
+	stmt := "CREATE "
+	if idx.Unique {
+		stmt += "UNIQUE "
+	}
+	switch indexMethod(idx) {
+	case "clustered":
+		stmt += "CLUSTERED "
+	case "nonclustered":
+		stmt += "NONCLUSTERED "
+	}
+	stmt += fmt.Sprintf("INDEX %s ON %s (%s)", quoteIdentMSSQL(indexName(table, idx)), quoteIdentMSSQL(table), indexColumnList(idx, quoteIdentMSSQL))
+	if len(idx.Include) > 0 {
+		stmt += fmt.Sprintf(" INCLUDE (%s)", quoteList(idx.Include, quoteIdentMSSQL))
+	}
+	if where := strings.TrimSpace(idx.Where); where != "" {
+		stmt += " WHERE " + where
+	}
+	return stmt + ";"
+}
+
+// indexMySQL renders an index as a CREATE TABLE clause. MySQL has neither
+// partial indexes nor INCLUDE columns, so those are left out; fulltext and
+// spatial are index kinds there rather than methods.
+func indexMySQL(table string, idx model.Index) string {
+	name := quoteIdentMySQL(indexName(table, idx))
+	cols := indexColumnList(idx, quoteIdentMySQL)
+
+	method := indexMethod(idx)
+	if method == "fulltext" || method == "spatial" {
+		return fmt.Sprintf("%s KEY %s (%s)", strings.ToUpper(method), name, cols)
+	}
+
+	clause := fmt.Sprintf("KEY %s (%s)", name, cols)
+	if idx.Unique {
+		clause = "UNIQUE " + clause
+	}
+	if method == "btree" || method == "hash" {
+		clause += " USING " + strings.ToUpper(method)
+	}
+	return clause
+}
+
+// createIndexMongo renders a createIndex call. Methods with a Mongo
+// counterpart (hashed, text, 2dsphere) replace the sort direction; partial
+// predicates are SQL and cannot be carried over.
+func createIndexMongo(table string, idx model.Index) string {
+	kind := mongoIndexKind(idx.Method)
+	if idx.Unique {
+		// Only ordinary indexes can be unique, not hashed, text or 2dsphere ones
+		kind = ""
+	}
+
+	keys := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		value := "1"
+		switch {
+		case kind != "":
+			value = kind
+		case col.IsDescending():
+			value = "-1"
+		}
+		keys[i] = quoteJS(col.Name) + ": " + value
+	}
+
+	options := []string{"name: " + quoteJS(indexName(table, idx))}
+	if idx.Unique {
+		options = append(options, "unique: true")
+	}
+	return fmt.Sprintf("%s.createIndex({ %s }, { %s });", mongoCollection(table), strings.Join(keys, ", "), strings.Join(options, ", "))
+}
+
+// mongoIndexKind returns the key value for special index methods, or "" for
+// ordinary ascending or descending indexes
+func mongoIndexKind(method string) string {
+	switch strings.ToLower(method) {
+	case "hash", "hashed":
+		return `"hashed"`
+	case "text", "fulltext":
+		return `"text"`
+	case "2dsphere", "spatial":
+		return `"2dsphere"`
+	}
+	return ""
+}
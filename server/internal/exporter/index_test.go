@@ -0,0 +1,47 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestCreateIndexUniqueMethods(t *testing.T) {
+	cols := []model.IndexColumn{{Name: "email"}}
+	tests := []struct {
+		idx      model.Index
+		postgres string
+		mongo    string
+	}{
+		{
+			idx:      model.Index{Name: "ix", Columns: cols, Method: "hash"},
+			postgres: `CREATE INDEX "ix" ON "users" USING hash ("email");`,
+			mongo:    `db.users.createIndex({ "email": "hashed" }, { name: "ix" });`,
+		},
+		{
+			// Postgres rejects unique hash indexes, and Mongo unique hashed ones
+			idx:      model.Index{Name: "ix", Columns: cols, Method: "hash", Unique: true},
+			postgres: `CREATE UNIQUE INDEX "ix" ON "users" ("email");`,
+			mongo:    `db.users.createIndex({ "email": 1 }, { name: "ix", unique: true });`,
+		},
+		{
+			idx:      model.Index{Name: "ix", Columns: cols, Method: "gin", Unique: true},
+			postgres: `CREATE UNIQUE INDEX "ix" ON "users" ("email");`,
+			mongo:    `db.users.createIndex({ "email": 1 }, { name: "ix", unique: true });`,
+		},
+		{
+			idx:      model.Index{Name: "ix", Columns: cols, Method: "btree", Unique: true},
+			postgres: `CREATE UNIQUE INDEX "ix" ON "users" USING btree ("email");`,
+			mongo:    `db.users.createIndex({ "email": 1 }, { name: "ix", unique: true });`,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := createIndexPostgres("users", tt.idx); got != tt.postgres {
+			t.Errorf("postgres %+v:\n got %s\nwant %s", tt.idx, got, tt.postgres)
+		}
+		if got := createIndexMongo("users", tt.idx); got != tt.mongo {
+			t.Errorf("mongo %+v:\n got %s\nwant %s", tt.idx, got, tt.mongo)
+		}
+	}
+}
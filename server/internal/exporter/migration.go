@@ -0,0 +1,638 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/diff"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// Migration holds the scripts that move a database between two versions of a
+// schema, in both directions
+type Migration struct {
+	Up   string `json:"up"`
+	Down string `json:"down"`
+}
+
+// GenerateMigration writes ALTER-based scripts that turn a database created
+// from one schema into one matching the other, and back. Only Postgres and
+// MySQL are supported.
+func GenerateMigration(from, to model.SchemaData, format ExportFormat) (Migration, error) {
+	var generate func(from, to model.SchemaData, direction string) string
+	switch format {
+	case FormatPostgres:
+		generate = migrationPostgres
+	case FormatMySQL:
+		generate = migrationMySQL
+	default:
+		return Migration{}, fmt.Errorf("migrations are not supported for format: %s", format)
+	}
+	for _, schema := range []model.SchemaData{from, to} {
+		if err := checkTypes(schema); err != nil {
+			return Migration{}, err
+		}
+	}
+	return Migration{
+		Up:   generate(from, to, "up"),
+		Down: generate(to, from, "down"),
+	}, nil
+}
+
+type migrationPhase int
+
+// Statements run phase by phase, so that everything is dropped before it is
+// recreated and foreign keys are added once the tables and keys they point
+// at exist
+const (
+	phaseDropForeignKeys migrationPhase = iota
+	phaseDropConstraints
+	phaseDropColumns
+	phaseDropTables
+	phaseRenameTables
+	phaseCreateTypes
+	phaseCreateTables
+	phaseAddColumns
+	phaseAlterColumns
+	phaseAddConstraints
+	phaseAddForeignKeys
+	phaseComments
+	phaseCount
+)
+
+// migration collects the statements of one script. Statements that run
+// before tables are renamed use the old table names.
+type migration struct {
+	from, to   map[string]*model.Table
+	renames    map[string]string
+	oldNames   map[string]string
+	changes    []diff.Change
+	statements [phaseCount][]string
+}
+
+func newMigration(from, to model.SchemaData) *migration {
+	m := &migration{
+		from:     tableMap(from.Tables),
+		to:       tableMap(to.Tables),
+		renames:  make(map[string]string),
+		oldNames: make(map[string]string),
+		changes:  diff.Compare(from, to),
+	}
+	for _, ch := range m.changes {
+		if ch.Kind == diff.KindTableRenamed {
+			m.renames[ch.From] = ch.To
+			m.oldNames[ch.To] = ch.From
+		}
+	}
+	return m
+}
+
+func tableMap(tables []model.Table) map[string]*model.Table {
+	byName := make(map[string]*model.Table, len(tables))
+	for i := range tables {
+		if _, exists := byName[tables[i].Name]; !exists {
+			byName[tables[i].Name] = &tables[i]
+		}
+	}
+	return byName
+}
+
+func (m *migration) add(phase migrationPhase, stmt string) {
+	m.statements[phase] = append(m.statements[phase], stmt)
+}
+
+// comment adds a hint for the reader, kept on one line so that a line break
+// in a name cannot end the comment early
+func (m *migration) comment(phase migrationPhase, format string, args ...interface{}) {
+	m.add(phase, "-- "+singleLine(fmt.Sprintf(format, args...)))
+}
+
+// oldName returns the name a table of the new schema had in the old one
+func (m *migration) oldName(table string) string {
+	if old, ok := m.oldNames[table]; ok {
+		return old
+	}
+	return table
+}
+
+func (m *migration) oldColumn(ch diff.Change) model.Column {
+	return *findTableColumn(*m.from[m.oldName(ch.Table)], ch.Column)
+}
+
+func (m *migration) newColumn(ch diff.Change) model.Column {
+	return *findTableColumn(*m.to[ch.Table], ch.Column)
+}
+
+// oldForeignKey finds the removed key a change describes. The diff shows
+// references to renamed tables under their new name.
+func (m *migration) oldForeignKey(ch diff.Change) model.ForeignKey {
+	for _, fk := range m.from[m.oldName(ch.Table)].ForeignKeys {
+		described := fk
+		if name, ok := m.renames[fk.References.Table]; ok {
+			described.References.Table = name
+		}
+		if diff.DescribeForeignKey(described) == ch.From {
+			return fk
+		}
+	}
+	return model.ForeignKey{}
+}
+
+func (m *migration) newForeignKey(ch diff.Change) model.ForeignKey {
+	for _, fk := range m.to[ch.Table].ForeignKeys {
+		if diff.DescribeForeignKey(fk) == ch.To {
+			return fk
+		}
+	}
+	return model.ForeignKey{}
+}
+
+func findIndex(table *model.Table, described string) model.Index {
+	for _, idx := range table.Indexes {
+		if diff.DescribeIndex(idx) == described {
+			return idx
+		}
+	}
+	return model.Index{}
+}
+
+// findConstraint returns the UNIQUE or CHECK constraint a change describes;
+// exactly one of the results is set
+func findConstraint(table *model.Table, described string) (*model.Unique, *model.Check) {
+	for i := range table.Uniques {
+		if diff.DescribeUnique(table.Uniques[i]) == described {
+			return &table.Uniques[i], nil
+		}
+	}
+	for i := range table.Checks {
+		if diff.DescribeCheck(table.Checks[i]) == described {
+			return nil, &table.Checks[i]
+		}
+	}
+	return nil, nil
+}
+
+// removedTables returns the dropped tables with referencing tables first
+func (m *migration) removedTables() []model.Table {
+	var tables []model.Table
+	for _, ch := range m.changes {
+		if ch.Kind == diff.KindTableRemoved {
+			tables = append(tables, *m.from[ch.Table])
+		}
+	}
+	ordered, _ := orderTables(tables)
+	for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	}
+	return ordered
+}
+
+// renameHint returns the added column a removed one may have been renamed
+// to: the only column added to the same table with the same type
+func (m *migration) renameHint(removed diff.Change) (string, bool) {
+	old := m.oldColumn(removed)
+	match := ""
+	for _, ch := range m.changes {
+		if ch.Kind != diff.KindColumnAdded || ch.Table != removed.Table || !sameColumnType(old, m.newColumn(ch)) {
+			continue
+		}
+		if match != "" {
+			return "", false
+		}
+		match = ch.Column
+	}
+	return match, match != ""
+}
+
+func sameColumnType(a, b model.Column) bool {
+	return strings.EqualFold(a.Type, b.Type) && a.Length == b.Length && a.Precision == b.Precision &&
+		a.Scale == b.Scale && a.Unsigned == b.Unsigned && strings.Join(a.EnumValues, "\x00") == strings.Join(b.EnumValues, "\x00")
+}
+
+func (m *migration) script(title string) string {
+	var sb strings.Builder
+	sb.WriteString("-- " + title + "\n")
+	sb.WriteString("-- Generated by DB Schema Generator\n\n")
+
+	empty := true
+	for _, stmts := range m.statements {
+		if len(stmts) == 0 {
+			continue
+		}
+		if !empty {
+			sb.WriteString("\n")
+		}
+		empty = false
+		for _, stmt := range stmts {
+			sb.WriteString(stmt + "\n")
+		}
+	}
+	if empty {
+		sb.WriteString("-- No changes\n")
+	}
+	return sb.String()
+}
+
+// pgDefaultName returns the name Postgres gives an unnamed constraint
+func pgDefaultName(table string, columns []string, suffix string) string {
+	if len(columns) == 0 {
+		return fmt.Sprintf("%s_%s", table, suffix)
+	}
+	return fmt.Sprintf("%s_%s_%s", table, strings.Join(columns, "_"), suffix)
+}
+
+func migrationPostgres(from, to model.SchemaData, direction string) string {
+	m := newMigration(from, to)
+	q := quoteIdentANSI
+
+	for _, ch := range m.changes {
+		table := q(ch.Table)
+		oldTable := q(m.oldName(ch.Table))
+
+		switch ch.Kind {
+		case diff.KindTableAdded:
+			t := *m.to[ch.Table]
+			for _, col := range t.Columns {
+				if isEnumColumn(col) {
+					m.add(phaseCreateTypes, createEnumPostgres(t.Name, col))
+				}
+			}
+			for _, fk := range t.ForeignKeys {
+				m.add(phaseAddForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, foreignKeyANSI(namedForeignKey(t.Name, fk))))
+			}
+			t.ForeignKeys = nil
+			m.add(phaseCreateTables, strings.TrimSuffix(createTablePostgres(t), "\n"))
+
+		case diff.KindTableRenamed:
+			m.comment(phaseRenameTables, "%s and %s have the same columns, so this is taken as a rename", ch.From, ch.To)
+			m.add(phaseRenameTables, fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", oldTable, table))
+			// Enum types are named after their table
+			for _, col := range m.to[ch.Table].Columns {
+				if old := findTableColumn(*m.from[ch.From], col.Name); old != nil && isEnumColumn(*old) {
+					m.add(phaseRenameTables, fmt.Sprintf("ALTER TYPE %s RENAME TO %s;", enumTypePostgres(ch.From, col.Name), q(fmt.Sprintf("%s_%s_enum", ch.To, col.Name))))
+				}
+			}
+
+		case diff.KindColumnAdded:
+			col := m.newColumn(ch)
+			if isEnumColumn(col) {
+				m.add(phaseCreateTypes, createEnumPostgres(ch.Table, col))
+			}
+			if col.NotNull && col.Default == nil && !col.AutoIncrement {
+				m.comment(phaseAddColumns, "%s.%s is NOT NULL without a default, so this fails if the table has rows", ch.Table, col.Name)
+			}
+			m.add(phaseAddColumns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, columnDefPostgres(ch.Table, col)))
+
+		case diff.KindColumnRemoved:
+			if name, ok := m.renameHint(ch); ok {
+				m.comment(phaseDropColumns, "%s.%s was dropped and %s added with the same type; if it was renamed, use instead:", ch.Table, ch.Column, name)
+				m.comment(phaseDropColumns, "ALTER TABLE %s RENAME COLUMN %s TO %s;", oldTable, q(ch.Column), q(name))
+			}
+			m.add(phaseDropColumns, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", oldTable, q(ch.Column)))
+			if isEnumColumn(m.oldColumn(ch)) {
+				m.add(phaseDropColumns, fmt.Sprintf("DROP TYPE %s;", enumTypePostgres(m.oldName(ch.Table), ch.Column)))
+			}
+
+		case diff.KindTypeChanged:
+			old, col := m.oldColumn(ch), m.newColumn(ch)
+			newType := typePostgres(ch.Table, col)
+			using := fmt.Sprintf("%s::%s", q(col.Name), newType)
+			if isEnumColumn(old) || isEnumColumn(col) {
+				using = fmt.Sprintf("%s::text::%s", q(col.Name), newType)
+			}
+			if isEnumColumn(col) && !isEnumColumn(old) {
+				m.add(phaseCreateTypes, createEnumPostgres(ch.Table, col))
+			}
+			m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s;", table, q(col.Name), newType, using))
+			if isEnumColumn(old) && !isEnumColumn(col) {
+				m.add(phaseAlterColumns, fmt.Sprintf("DROP TYPE %s;", enumTypePostgres(ch.Table, col.Name)))
+			}
+
+		case diff.KindNullabilityChanged:
+			action := "DROP NOT NULL"
+			if m.newColumn(ch).NotNull {
+				action = "SET NOT NULL"
+			}
+			m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;", table, q(ch.Column), action))
+
+		case diff.KindDefaultChanged:
+			old, col := m.oldColumn(ch), m.newColumn(ch)
+			if col.AutoIncrement || replacesEnumPostgres(old, col) {
+				// The sequence is the default, or the type replacement sets it
+				continue
+			}
+			action := "DROP DEFAULT"
+			if col.Default != nil {
+				action = "SET DEFAULT " + formatDefaultPostgres(*col.Default, col.Type)
+			}
+			m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;", table, q(ch.Column), action))
+
+		case diff.KindEnumChanged:
+			old, col := m.oldColumn(ch), m.newColumn(ch)
+			if isEnumColumn(old) && isEnumColumn(col) {
+				m.alterEnumPostgres(ch.Table, old, col)
+			}
+
+		case diff.KindAutoIncrementChanged:
+			col := m.newColumn(ch)
+			seq := fmt.Sprintf("%s_%s_seq", ch.Table, col.Name)
+			if col.AutoIncrement {
+				// What SERIAL does, for an existing column
+				m.add(phaseAlterColumns, fmt.Sprintf("CREATE SEQUENCE %s OWNED BY %s.%s;", q(seq), table, q(col.Name)))
+				m.add(phaseAlterColumns, fmt.Sprintf("SELECT setval(%s, COALESCE(MAX(%s), 0) + 1, false) FROM %s;", quoteStringANSI(q(seq)), q(col.Name), table))
+				m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT nextval(%s);", table, q(col.Name), quoteStringANSI(q(seq))))
+			} else {
+				m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", table, q(col.Name)))
+				m.add(phaseAlterColumns, fmt.Sprintf("DROP SEQUENCE IF EXISTS %s;", q(seq)))
+				if col.Default != nil {
+					m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", table, q(col.Name), formatDefaultPostgres(*col.Default, col.Type)))
+				}
+			}
+
+		case diff.KindUniqueChanged:
+			old, col := m.oldColumn(ch), m.newColumn(ch)
+			if old.Unique && !old.PrimaryKey {
+				name := pgDefaultName(m.oldName(ch.Table), []string{ch.Column}, "key")
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", oldTable, q(name)))
+			}
+			if col.Unique && !col.PrimaryKey {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD UNIQUE (%s);", table, q(ch.Column)))
+			}
+
+		case diff.KindPrimaryKeyChanged:
+			if ch.From != "" {
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", oldTable, q(pgDefaultName(m.oldName(ch.Table), nil, "pkey"))))
+			}
+			if ch.To != "" {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", table, quoteList(primaryKeyColumns(*m.to[ch.Table]), q)))
+			}
+
+		case diff.KindForeignKeyAdded:
+			m.add(phaseAddForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, foreignKeyANSI(namedForeignKey(ch.Table, m.newForeignKey(ch)))))
+
+		case diff.KindForeignKeyRemoved, diff.KindForeignKeyChanged:
+			fk := namedForeignKey(m.oldName(ch.Table), m.oldForeignKey(ch))
+			m.add(phaseDropForeignKeys, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", oldTable, q(fk.Name)))
+			if ch.Kind == diff.KindForeignKeyChanged {
+				m.add(phaseAddForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, foreignKeyANSI(namedForeignKey(ch.Table, m.newForeignKey(ch)))))
+			}
+
+		case diff.KindIndexAdded:
+			m.add(phaseAddConstraints, createIndexPostgres(ch.Table, findIndex(m.to[ch.Table], ch.To)))
+
+		case diff.KindIndexRemoved:
+			idx := findIndex(m.from[m.oldName(ch.Table)], ch.From)
+			m.add(phaseDropConstraints, fmt.Sprintf("DROP INDEX %s;", q(indexName(m.oldName(ch.Table), idx))))
+
+		case diff.KindConstraintAdded:
+			u, check := findConstraint(m.to[ch.Table], ch.To)
+			if u != nil {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, uniqueClause(*u, q)))
+			} else if check != nil {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, checkClause(*check, q)))
+			}
+
+		case diff.KindConstraintRemoved:
+			u, check := findConstraint(m.from[m.oldName(ch.Table)], ch.From)
+			switch {
+			case u != nil:
+				name := u.Name
+				if name == "" {
+					name = pgDefaultName(m.oldName(ch.Table), u.Columns, "key")
+				}
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", oldTable, q(name)))
+			case check != nil && check.Name != "":
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", oldTable, q(check.Name)))
+			case check != nil:
+				m.comment(phaseDropConstraints, "%s has no name; drop it from %s by the name Postgres generated for it", ch.From, ch.Table)
+			}
+
+		case diff.KindCommentChanged:
+			if ch.Column != "" {
+				m.add(phaseComments, commentOnColumnPostgres(ch.Table, ch.Column, ch.To))
+			} else {
+				m.add(phaseComments, commentOnTablePostgres(ch.Table, ch.To))
+			}
+		}
+	}
+
+	for _, t := range m.removedTables() {
+		m.add(phaseDropTables, fmt.Sprintf("DROP TABLE %s;", q(t.Name)))
+		for _, col := range t.Columns {
+			if isEnumColumn(col) {
+				m.add(phaseDropTables, fmt.Sprintf("DROP TYPE %s;", enumTypePostgres(t.Name, col.Name)))
+			}
+		}
+	}
+
+	return m.script("PostgreSQL Migration (" + direction + ")")
+}
+
+// alterEnumPostgres adds the new values of an enum in place when all of the
+// old ones are kept in order. Otherwise the type is replaced, which fails
+// while rows still hold a removed value.
+func (m *migration) alterEnumPostgres(table string, old, col model.Column) {
+	typeName := enumTypePostgres(table, col.Name)
+
+	if !replacesEnumPostgres(old, col) {
+		for i, v := range col.EnumValues {
+			if containsString(old.EnumValues, v) {
+				continue
+			}
+			var position string
+			if i > 0 {
+				position = "AFTER " + quoteStringANSI(col.EnumValues[i-1])
+			} else {
+				position = "BEFORE " + quoteStringANSI(col.EnumValues[i+1])
+			}
+			m.add(phaseAlterColumns, fmt.Sprintf("ALTER TYPE %s ADD VALUE %s %s;", typeName, quoteStringANSI(v), position))
+		}
+		return
+	}
+
+	var removed []string
+	for _, v := range old.EnumValues {
+		if !containsString(col.EnumValues, v) {
+			removed = append(removed, v)
+		}
+	}
+	if len(removed) > 0 {
+		m.comment(phaseAlterColumns, "rows of %s holding %s have to be updated first", table, strings.Join(removed, ", "))
+	}
+
+	oldType := quoteIdentANSI(fmt.Sprintf("%s_%s_enum_old", table, col.Name))
+	qt, qc := quoteIdentANSI(table), quoteIdentANSI(col.Name)
+	m.add(phaseAlterColumns, fmt.Sprintf("ALTER TYPE %s RENAME TO %s;", typeName, oldType))
+	m.add(phaseAlterColumns, createEnumPostgres(table, col))
+	// A default of the old type would block the conversion
+	if old.Default != nil || col.Default != nil {
+		m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", qt, qc))
+	}
+	m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::text::%s;", qt, qc, typeName, qc, typeName))
+	m.add(phaseAlterColumns, fmt.Sprintf("DROP TYPE %s;", oldType))
+	if col.Default != nil {
+		m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", qt, qc, formatDefaultPostgres(*col.Default, col.Type)))
+	}
+}
+
+// replacesEnumPostgres reports whether a change of enum values needs a new
+// type, because Postgres can add values to a type but not remove or reorder
+// them
+func replacesEnumPostgres(old, col model.Column) bool {
+	return isEnumColumn(old) && isEnumColumn(col) && !isSubsequence(old.EnumValues, col.EnumValues)
+}
+
+// isSubsequence reports whether every element of sub appears in list in the
+// same order
+func isSubsequence(sub, list []string) bool {
+	i := 0
+	for _, v := range list {
+		if i < len(sub) && sub[i] == v {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+func primaryKeyColumns(table model.Table) []string {
+	var cols []string
+	for _, col := range table.Columns {
+		if col.PrimaryKey {
+			cols = append(cols, col.Name)
+		}
+	}
+	return cols
+}
+
+func migrationMySQL(from, to model.SchemaData, direction string) string {
+	m := newMigration(from, to)
+	q := quoteIdentMySQL
+	modified := make(map[string]bool)
+
+	for _, ch := range m.changes {
+		table := q(ch.Table)
+		oldTable := q(m.oldName(ch.Table))
+
+		switch ch.Kind {
+		case diff.KindTableAdded:
+			t := *m.to[ch.Table]
+			for _, fk := range t.ForeignKeys {
+				m.add(phaseAddForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, foreignKeyMySQL(namedForeignKey(t.Name, fk))))
+			}
+			t.ForeignKeys = nil
+			m.add(phaseCreateTables, strings.TrimSuffix(createTableMySQL(t), "\n"))
+
+		case diff.KindTableRenamed:
+			m.comment(phaseRenameTables, "%s and %s have the same columns, so this is taken as a rename", ch.From, ch.To)
+			m.add(phaseRenameTables, fmt.Sprintf("RENAME TABLE %s TO %s;", oldTable, table))
+
+		case diff.KindColumnAdded:
+			position := "FIRST"
+			columns := m.to[ch.Table].Columns
+			for i, col := range columns {
+				if col.Name == ch.Column && i > 0 {
+					position = "AFTER " + q(columns[i-1].Name)
+				}
+			}
+			m.add(phaseAddColumns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, columnDefMySQL(m.newColumn(ch)), position))
+
+		case diff.KindColumnRemoved:
+			if name, ok := m.renameHint(ch); ok {
+				m.comment(phaseDropColumns, "%s.%s was dropped and %s added with the same type; if it was renamed, use instead:", ch.Table, ch.Column, name)
+				m.comment(phaseDropColumns, "ALTER TABLE %s RENAME COLUMN %s TO %s;", oldTable, q(ch.Column), q(name))
+			}
+			m.add(phaseDropColumns, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", oldTable, q(ch.Column)))
+
+		case diff.KindTypeChanged, diff.KindNullabilityChanged, diff.KindDefaultChanged,
+			diff.KindEnumChanged, diff.KindAutoIncrementChanged:
+			m.modifyColumnMySQL(ch, modified)
+
+		case diff.KindUniqueChanged:
+			// MySQL names the index of a UNIQUE column after the column
+			old, col := m.oldColumn(ch), m.newColumn(ch)
+			if old.Unique && !old.PrimaryKey {
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", oldTable, q(ch.Column)))
+			}
+			if col.Unique && !col.PrimaryKey {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD UNIQUE (%s);", table, q(ch.Column)))
+			}
+
+		case diff.KindPrimaryKeyChanged:
+			if ch.From != "" {
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", oldTable))
+			}
+			if ch.To != "" {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", table, quoteList(primaryKeyColumns(*m.to[ch.Table]), q)))
+			}
+
+		case diff.KindForeignKeyAdded:
+			m.add(phaseAddForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, foreignKeyMySQL(namedForeignKey(ch.Table, m.newForeignKey(ch)))))
+
+		case diff.KindForeignKeyRemoved, diff.KindForeignKeyChanged:
+			fk := namedForeignKey(m.oldName(ch.Table), m.oldForeignKey(ch))
+			m.add(phaseDropForeignKeys, fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", oldTable, q(fk.Name)))
+			if ch.Kind == diff.KindForeignKeyChanged {
+				m.add(phaseAddForeignKeys, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, foreignKeyMySQL(namedForeignKey(ch.Table, m.newForeignKey(ch)))))
+			}
+
+		case diff.KindIndexAdded:
+			m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, indexMySQL(ch.Table, findIndex(m.to[ch.Table], ch.To))))
+
+		case diff.KindIndexRemoved:
+			idx := findIndex(m.from[m.oldName(ch.Table)], ch.From)
+			m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", oldTable, q(indexName(m.oldName(ch.Table), idx))))
+
+		case diff.KindConstraintAdded:
+			u, check := findConstraint(m.to[ch.Table], ch.To)
+			if u != nil {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, uniqueClause(*u, q)))
+			} else if check != nil {
+				m.add(phaseAddConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, checkClause(*check, q)))
+			}
+
+		case diff.KindConstraintRemoved:
+			u, check := findConstraint(m.from[m.oldName(ch.Table)], ch.From)
+			switch {
+			case u != nil:
+				// An unnamed UNIQUE constraint is an index named after its first column
+				name := u.Name
+				if name == "" && len(u.Columns) > 0 {
+					name = u.Columns[0]
+				}
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", oldTable, q(name)))
+			case check != nil && check.Name != "":
+				m.add(phaseDropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CHECK %s;", oldTable, q(check.Name)))
+			case check != nil:
+				m.comment(phaseDropConstraints, "%s has no name; drop it from %s by the name MySQL generated for it", ch.From, ch.Table)
+			}
+
+		case diff.KindCommentChanged:
+			if ch.Column != "" {
+				m.modifyColumnMySQL(ch, modified)
+			} else {
+				m.add(phaseComments, fmt.Sprintf("ALTER TABLE %s COMMENT = %s;", table, quoteStringMySQL(ch.To)))
+			}
+		}
+	}
+
+	for _, t := range m.removedTables() {
+		m.add(phaseDropTables, fmt.Sprintf("DROP TABLE %s;", q(t.Name)))
+	}
+
+	return m.script("MySQL Migration (" + direction + ")")
+}
+
+// modifyColumnMySQL redefines a changed column. MODIFY COLUMN takes the whole
+// definition, so one statement covers every change to the column.
+func (m *migration) modifyColumnMySQL(ch diff.Change, modified map[string]bool) {
+	key := ch.Table + "." + ch.Column
+	if modified[key] {
+		return
+	}
+	modified[key] = true
+
+	col := m.newColumn(ch)
+	// Uniqueness is changed separately; repeating it would add another index
+	col.Unique = false
+	m.add(phaseAlterColumns, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", quoteIdentMySQL(ch.Table), columnDefMySQL(col)))
+}
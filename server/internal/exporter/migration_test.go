@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestMigrationMySQLDropsExportedForeignKey(t *testing.T) {
+	users := model.Table{Name: "users", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}}
+	posts := model.Table{
+		Name: "posts",
+		Columns: []model.Column{
+			{Name: "id", Type: "INT", PrimaryKey: true},
+			{Name: "author_id", Type: "INT"},
+		},
+		ForeignKeys: []model.ForeignKey{{Column: "author_id", References: model.Reference{Table: "users", Column: "id"}}},
+	}
+	from := model.SchemaData{Tables: []model.Table{users, posts}}
+	posts.ForeignKeys = nil
+	to := model.SchemaData{Tables: []model.Table{users, posts}}
+
+	out, err := Export(from, FormatMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "CONSTRAINT `fk_posts_author_id` FOREIGN KEY (`author_id`)") {
+		t.Errorf("export does not name the foreign key:\n%s", out)
+	}
+
+	migration, err := GenerateMigration(from, to, FormatMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(migration.Up, "ALTER TABLE `posts` DROP FOREIGN KEY `fk_posts_author_id`;") {
+		t.Errorf("up does not drop the exported key:\n%s", migration.Up)
+	}
+	if !strings.Contains(migration.Down, "CONSTRAINT `fk_posts_author_id`") {
+		t.Errorf("down does not restore the key under the same name:\n%s", migration.Down)
+	}
+}
+
+func TestMigrationPostgresDropsExportedForeignKeys(t *testing.T) {
+	users := model.Table{Name: "users", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}}
+	// The self-reference is added after the table, the other key inline
+	cat := model.Table{
+		Name: "cat",
+		Columns: []model.Column{
+			{Name: "id", Type: "INT", PrimaryKey: true},
+			{Name: "parent_id", Type: "INT"},
+			{Name: "owner_id", Type: "INT"},
+		},
+		ForeignKeys: []model.ForeignKey{
+			{Column: "parent_id", References: model.Reference{Table: "cat", Column: "id"}},
+			{Column: "owner_id", References: model.Reference{Table: "users", Column: "id"}},
+		},
+	}
+	from := model.SchemaData{Tables: []model.Table{users, cat}}
+	cat.ForeignKeys = nil
+	to := model.SchemaData{Tables: []model.Table{users, cat}}
+
+	out, err := Export(from, FormatPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migration, err := GenerateMigration(from, to, FormatPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"fk_cat_parent_id", "fk_cat_owner_id"} {
+		constraint := `CONSTRAINT "` + name + `" FOREIGN KEY`
+		if !strings.Contains(out, constraint) {
+			t.Errorf("export does not name the foreign key %s:\n%s", name, out)
+		}
+		if !strings.Contains(migration.Up, `ALTER TABLE "cat" DROP CONSTRAINT "`+name+`";`) {
+			t.Errorf("up does not drop the exported key %s:\n%s", name, migration.Up)
+		}
+		if !strings.Contains(migration.Down, constraint) {
+			t.Errorf("down does not restore the key %s under the same name:\n%s", name, migration.Down)
+		}
+	}
+}
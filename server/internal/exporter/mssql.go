@@ -0,0 +1,218 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func exportMSSQL(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("-- SQL Server (T-SQL) Schema Export\n")
+	sb.WriteString("-- Generated by DB Schema Generator\n\n")
+
+	tables, deferred := orderTables(schema.Tables)
+
+	for _, table := range tables {
+		sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", quoteIdentMSSQL(table.Name)))
+
+		var columns []string
+		var primaryKeys []string
+
+		for _, col := range table.Columns {
+			colDef := fmt.Sprintf("  %s %s", quoteIdentMSSQL(col.Name), sizedTypeMSSQL(mapTypeToMSSQL(col.Type), col))
+
+			if col.AutoIncrement {
+				colDef += " IDENTITY(1,1)"
+			}
+
+			if col.NotNull || col.PrimaryKey || col.AutoIncrement {
+				colDef += " NOT NULL"
+			} else {
+				colDef += " NULL"
+			}
+
+			if col.Unique && !col.PrimaryKey {
+				colDef += " UNIQUE"
+			}
+
+			if col.Default != nil && !col.AutoIncrement {
+				colDef += fmt.Sprintf(" DEFAULT %s", formatDefaultMSSQL(*col.Default, col.Type))
+			}
+
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				enumVals := make([]string, len(col.EnumValues))
+				for i, v := range col.EnumValues {
+					enumVals[i] = quoteStringMSSQL(v)
+				}
+				colDef += fmt.Sprintf(" CHECK (%s IN (%s))", quoteIdentMSSQL(col.Name), strings.Join(enumVals, ", "))
+			}
+
+			columns = append(columns, colDef)
+
+			if col.PrimaryKey {
+				primaryKeys = append(primaryKeys, quoteIdentMSSQL(col.Name))
+			}
+		}
+
+		if len(primaryKeys) > 0 {
+			columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+		}
+
+		for _, u := range table.Uniques {
+			columns = append(columns, "  "+uniqueClause(u, quoteIdentMSSQL))
+		}
+
+		for _, ch := range table.Checks {
+			columns = append(columns, "  "+checkClause(ch, quoteIdentMSSQL))
+		}
+
+		for _, fk := range table.ForeignKeys {
+			columns = append(columns, "  "+foreignKeyMSSQL(fk))
+		}
+
+		sb.WriteString(strings.Join(columns, ",\n"))
+		sb.WriteString("\n);\nGO\n")
+
+		for _, idx := range table.Indexes {
+			sb.WriteString(createIndexMSSQL(table.Name, idx) + "\nGO\n")
+		}
+
+		// SQL Server keeps comments as MS_Description extended properties
+		if table.Comment != "" {
+			sb.WriteString(describeMSSQL(table.Comment, table.Name, "") + "\nGO\n")
+		}
+		for _, col := range table.Columns {
+			if col.Comment != "" {
+				sb.WriteString(describeMSSQL(col.Comment, table.Name, col.Name) + "\nGO\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Foreign keys in cycles are added once both ends exist
+	for _, d := range deferred {
+		sb.WriteString(fmt.Sprintf("ALTER TABLE %s ADD %s;\nGO\n", quoteIdentMSSQL(d.Table), foreignKeyMSSQL(d.ForeignKey)))
+	}
+
+	return sb.String(), nil
+}
+
+// describeMSSQL adds the MS_Description of a table, or of one of its columns
+// when column is set
+func describeMSSQL(comment, table, column string) string {
+	stmt := fmt.Sprintf("EXEC sp_addextendedproperty @name = N'MS_Description', @value = %s, "+
+		"@level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'TABLE', @level1name = %s",
+		quoteStringMSSQL(comment), quoteStringMSSQL(table))
+	if column != "" {
+		stmt += fmt.Sprintf(", @level2type = N'COLUMN', @level2name = %s", quoteStringMSSQL(column))
+	}
+	return stmt + ";"
+}
+
+func mapTypeToMSSQL(t string) string {
+	upper := strings.ToUpper(t)
+	switch upper {
+	case "INTEGER", "INT", "MEDIUMINT", "SERIAL":
+		return "INT"
+	case "BIGSERIAL":
+		return "BIGINT"
+	case "BOOLEAN", "BOOL", "TINYINT(1)":
+		return "BIT"
+	case "DOUBLE", "DOUBLE PRECISION":
+		return "FLOAT"
+	case "NUMERIC":
+		return "DECIMAL"
+	case "ENUM", "SET":
+		return "NVARCHAR(255)"
+	case "VARCHAR":
+		return "NVARCHAR"
+	case "CHAR":
+		return "NCHAR"
+	case "TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "JSON", "JSONB":
+		return "NVARCHAR(MAX)"
+	case "DATETIME", "TIMESTAMP":
+		return "DATETIME2"
+	case "TIMESTAMPTZ":
+		return "DATETIMEOFFSET"
+	case "UUID":
+		return "UNIQUEIDENTIFIER"
+	case "BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB":
+		return "VARBINARY(MAX)"
+	case "YEAR":
+		return "SMALLINT"
+	case "INTERVAL":
+		return "NVARCHAR(64)"
+	default:
+		return upper
+	}
+}
+
+// sizedTypeMSSQL sizes a mapped type, falling back to MAX where a length is
+// missing or beyond what SQL Server allows inline
+func sizedTypeMSSQL(mapped string, col model.Column) string {
+	switch mapped {
+	case "NVARCHAR":
+		if col.Length > 4000 {
+			return "NVARCHAR(MAX)"
+		}
+		return sizedType(mapped, col, 255)
+	case "VARBINARY":
+		if col.Length == 0 || col.Length > 8000 {
+			return "VARBINARY(MAX)"
+		}
+	}
+	return sizedType(mapped, col, 0)
+}
+
+// foreignKeyMSSQL renders a foreign key clause for SQL Server, which supports
+// neither MATCH nor deferred checking
+func foreignKeyMSSQL(fk model.ForeignKey) string {
+	def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)",
+		quoteList(fk.SourceColumns(), quoteIdentMSSQL), quoteIdentMSSQL(fk.References.Table),
+		quoteList(fk.TargetColumns(), quoteIdentMSSQL))
+	if fk.Name != "" {
+		def = fmt.Sprintf("CONSTRAINT %s %s", quoteIdentMSSQL(fk.Name), def)
+	}
+	if action := mapReferentialActionMSSQL(fk.OnDelete); action != "" {
+		def += fmt.Sprintf(" ON DELETE %s", action)
+	}
+	if action := mapReferentialActionMSSQL(fk.OnUpdate); action != "" {
+		def += fmt.Sprintf(" ON UPDATE %s", action)
+	}
+	return def
+}
+
+// mapReferentialActionMSSQL rewrites actions SQL Server does not support
+func mapReferentialActionMSSQL(action string) string {
+	upper := referentialAction(action)
+	if upper == "RESTRICT" {
+		return "NO ACTION"
+	}
+	return upper
+}
+
+func formatDefaultMSSQL(def string, colType string) string {
+	upper := strings.ToUpper(def)
+	if upper == "NULL" || upper == "CURRENT_TIMESTAMP" {
+		return upper
+	}
+	if upper == "NOW()" {
+		return "CURRENT_TIMESTAMP"
+	}
+	if upper == "GEN_RANDOM_UUID()" || upper == "UUID()" || upper == "UUID_GENERATE_V4()" {
+		return "NEWID()"
+	}
+	if upper == "TRUE" {
+		return "1"
+	}
+	if upper == "FALSE" {
+		return "0"
+	}
+	if isNumericLiteral(def) {
+		return def
+	}
+	return quoteStringMSSQL(def)
+}
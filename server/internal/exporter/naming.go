@@ -0,0 +1,76 @@
+package exporter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// splitWords breaks an identifier such as "user_id", "userId" or "User ID" into
+// its lowercase words
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(current) > 0 &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+// toPascalCase converts an identifier to PascalCase, e.g. "order_items" -> "OrderItems"
+func toPascalCase(s string) string {
+	var sb strings.Builder
+	for _, w := range splitWords(s) {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
+
+// toCamelCase converts an identifier to camelCase, e.g. "order_items" -> "orderItems"
+func toCamelCase(s string) string {
+	p := []rune(toPascalCase(s))
+	if len(p) == 0 {
+		return ""
+	}
+	p[0] = unicode.ToLower(p[0])
+	return string(p)
+}
+
+// sanitizeIdentifier makes s usable as a bare identifier in generated code,
+// replacing unsupported characters with underscores
+func sanitizeIdentifier(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	out := sb.String()
+	if out == "" || unicode.IsDigit(rune(out[0])) {
+		out = "_" + out
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// deferredForeignKey is a foreign key that has to be added with ALTER TABLE
+// once every table exists. It always carries a constraint name.
+type deferredForeignKey struct {
+	Table      string
+	ForeignKey model.ForeignKey
+}
+
+// orderTables sorts tables so that each one is created after the tables its
+// foreign keys reference, keeping the designer's order wherever possible.
+// Self-references and foreign keys closing a cycle cannot be declared inline;
+// they are removed from the returned tables and returned separately.
+func orderTables(tables []model.Table) ([]model.Table, []deferredForeignKey) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	index := make(map[string]int, len(tables))
+	for i := len(tables) - 1; i >= 0; i-- {
+		index[tables[i].Name] = i
+	}
+
+	state := make([]int, len(tables))
+	ordered := make([]model.Table, 0, len(tables))
+	var deferred []deferredForeignKey
+
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = visiting
+
+		table := tables[i]
+		inline := make([]model.ForeignKey, 0, len(table.ForeignKeys))
+		for _, fk := range table.ForeignKeys {
+			target, ok := index[fk.References.Table]
+			switch {
+			case !ok:
+				// Unknown tables are left for the database to report
+				inline = append(inline, fk)
+			case target == i || state[target] == visiting:
+				deferred = append(deferred, deferredForeignKey{Table: table.Name, ForeignKey: namedForeignKey(table.Name, fk)})
+			default:
+				if state[target] == unvisited {
+					visit(target)
+				}
+				inline = append(inline, fk)
+			}
+		}
+
+		table.ForeignKeys = inline
+		ordered = append(ordered, table)
+		state[i] = done
+	}
+
+	for i := range tables {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+
+	return ordered, deferred
+}
+
+// foreignKeyConstraintName names a foreign key the schema leaves unnamed
+func foreignKeyConstraintName(table string, fk model.ForeignKey) string {
+	return fmt.Sprintf("fk_%s_%s", table, strings.Join(fk.SourceColumns(), "_"))
+}
+
+// namedForeignKey gives an unnamed key the name exports and migrations use
+// for it, so that a migration can drop a key the export created
+func namedForeignKey(table string, fk model.ForeignKey) model.ForeignKey {
+	if fk.Name == "" {
+		fk.Name = foreignKeyConstraintName(table, fk)
+	}
+	return fk
+}
@@ -0,0 +1,437 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+type prismaField struct {
+	name    string
+	typ     string
+	attrs   []string
+	comment string
+}
+
+type prismaModel struct {
+	name       string
+	table      string
+	comment    string
+	fields     []prismaField
+	blockAttrs []string
+	used       map[string]bool
+}
+
+// addField appends a field, suffixing the name when it clashes with an existing one
+func (m *prismaModel) addField(f prismaField) {
+	base := f.name
+	for i := 2; m.used[f.name]; i++ {
+		f.name = fmt.Sprintf("%s%d", base, i)
+	}
+	m.used[f.name] = true
+	m.fields = append(m.fields, f)
+}
+
+func exportPrisma(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("// Prisma Schema Export\n")
+	sb.WriteString("// Generated by DB Schema Generator\n\n")
+	sb.WriteString("generator client {\n  provider = \"prisma-client-js\"\n}\n\n")
+	sb.WriteString("datasource db {\n  provider = \"postgresql\"\n  url      = env(\"DATABASE_URL\")\n}\n\n")
+
+	models := make(map[string]*prismaModel)
+	var order []*prismaModel
+	var enums []string
+
+	for _, table := range schema.Tables {
+		m := &prismaModel{
+			name:    prismaIdentifier(toPascalCase(table.Name)),
+			table:   table.Name,
+			comment: table.Comment,
+			used:    make(map[string]bool),
+		}
+		models[table.Name] = m
+		order = append(order, m)
+
+		pkCount := 0
+		for _, col := range table.Columns {
+			if col.PrimaryKey {
+				pkCount++
+			}
+		}
+
+		var primaryKeys []string
+		for _, col := range table.Columns {
+			field := prismaField{name: prismaIdentifier(col.Name), comment: col.Comment}
+			if field.name != col.Name {
+				field.attrs = append(field.attrs, fmt.Sprintf("@map(%s)", quoteJS(col.Name)))
+			}
+
+			enumName := ""
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				enumName = m.name + prismaIdentifier(toPascalCase(col.Name))
+				enums = append(enums, renderPrismaEnum(enumName, col.EnumValues))
+				field.typ = enumName
+			} else {
+				field.typ = mapTypeToPrisma(col.Type)
+			}
+
+			if !col.NotNull && !col.PrimaryKey {
+				field.typ += "?"
+			}
+
+			if col.PrimaryKey {
+				primaryKeys = append(primaryKeys, field.name)
+				if pkCount == 1 {
+					field.attrs = append([]string{"@id"}, field.attrs...)
+				}
+			}
+			if col.Unique && !(col.PrimaryKey && pkCount == 1) {
+				field.attrs = append(field.attrs, "@unique")
+			}
+
+			if col.AutoIncrement {
+				field.attrs = append(field.attrs, "@default(autoincrement())")
+			} else if col.Default != nil {
+				field.attrs = append(field.attrs, fmt.Sprintf("@default(%s)", formatDefaultPrisma(*col.Default, col.Type, enumName != "")))
+			}
+			if native := prismaNativeType(col); native != "" && enumName == "" {
+				field.attrs = append(field.attrs, native)
+			}
+
+			m.addField(field)
+		}
+
+		if pkCount > 1 {
+			m.blockAttrs = append(m.blockAttrs, fmt.Sprintf("@@id([%s])", strings.Join(primaryKeys, ", ")))
+		}
+		for _, u := range table.Uniques {
+			m.blockAttrs = append(m.blockAttrs, prismaUnique(u))
+		}
+		for _, idx := range table.Indexes {
+			m.blockAttrs = append(m.blockAttrs, prismaIndex(idx))
+		}
+		if m.name != table.Name {
+			m.blockAttrs = append(m.blockAttrs, fmt.Sprintf("@@map(%s)", quoteJS(table.Name)))
+		}
+	}
+
+	// Relations need both sides declared, and a name whenever the same pair of
+	// models is linked more than once (including self references)
+	pairCount := make(map[string]int)
+	for _, table := range schema.Tables {
+		for _, fk := range table.ForeignKeys {
+			pairCount[prismaPairKey(table.Name, fk.References.Table)]++
+		}
+	}
+
+	for _, table := range schema.Tables {
+		owner := models[table.Name]
+		for _, fk := range table.ForeignKeys {
+			target, ok := models[fk.References.Table]
+			if !ok {
+				continue
+			}
+
+			source := fk.SourceColumns()
+			sourceKey := strings.Join(source, "_")
+
+			relationName := ""
+			if table.Name == fk.References.Table || pairCount[prismaPairKey(table.Name, fk.References.Table)] > 1 {
+				relationName = fmt.Sprintf("%s_%s", owner.name, prismaIdentifier(sourceKey))
+			}
+
+			// The relation is optional as soon as one of its columns is, and
+			// one-to-one only for a single unique column
+			nullable, unique := false, false
+			for _, name := range source {
+				if col := findTableColumn(table, name); col != nil {
+					nullable = nullable || (!col.NotNull && !col.PrimaryKey)
+					unique = len(source) == 1 && col.Unique
+				}
+			}
+
+			var relArgs []string
+			if relationName != "" {
+				relArgs = append(relArgs, quoteJS(relationName))
+			}
+			relArgs = append(relArgs,
+				fmt.Sprintf("fields: [%s]", prismaIdentifierList(source)),
+				fmt.Sprintf("references: [%s]", prismaIdentifierList(fk.TargetColumns())))
+			if fk.Name != "" {
+				relArgs = append(relArgs, "map: "+quoteJS(fk.Name))
+			}
+			if fk.OnDelete != "" {
+				relArgs = append(relArgs, "onDelete: "+mapReferentialActionPrisma(fk.OnDelete))
+			}
+			if fk.OnUpdate != "" {
+				relArgs = append(relArgs, "onUpdate: "+mapReferentialActionPrisma(fk.OnUpdate))
+			}
+
+			fieldType := target.name
+			if nullable {
+				fieldType += "?"
+			}
+			owner.addField(prismaField{
+				name:  prismaRelationFieldName(sourceKey, fk.References.Table),
+				typ:   fieldType,
+				attrs: []string{fmt.Sprintf("@relation(%s)", strings.Join(relArgs, ", "))},
+			})
+
+			backType := owner.name + "[]"
+			if unique {
+				backType = owner.name + "?"
+			}
+			backName := toCamelCase(table.Name)
+			if relationName != "" {
+				backName = toCamelCase(table.Name + "_by_" + sourceKey)
+			}
+			back := prismaField{name: prismaIdentifier(backName), typ: backType}
+			if relationName != "" {
+				back.attrs = []string{fmt.Sprintf("@relation(%s)", quoteJS(relationName))}
+			}
+			target.addField(back)
+		}
+	}
+
+	for _, e := range enums {
+		sb.WriteString(e)
+	}
+
+	for _, m := range order {
+		sb.WriteString(renderPrismaModel(m))
+	}
+
+	return sb.String(), nil
+}
+
+func renderPrismaEnum(name string, values []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("enum %s {\n", name))
+	for _, v := range values {
+		ident := prismaIdentifier(v)
+		if ident != v {
+			sb.WriteString(fmt.Sprintf("  %s @map(%s)\n", ident, quoteJS(v)))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s\n", ident))
+		}
+	}
+	sb.WriteString("}\n\n")
+	return sb.String()
+}
+
+func renderPrismaModel(m *prismaModel) string {
+	nameWidth, typeWidth := 0, 0
+	for _, f := range m.fields {
+		nameWidth = max(nameWidth, len(f.name))
+		typeWidth = max(typeWidth, len(f.typ))
+	}
+
+	var sb strings.Builder
+	// Triple-slash comments end up in the generated client's documentation
+	if m.comment != "" {
+		sb.WriteString(lineComment(m.comment, "", "///"))
+	}
+	sb.WriteString(fmt.Sprintf("model %s {\n", m.name))
+	for _, f := range m.fields {
+		if f.comment != "" {
+			sb.WriteString(lineComment(f.comment, "  ", "///"))
+		}
+		line := fmt.Sprintf("  %-*s %-*s %s", nameWidth, f.name, typeWidth, f.typ, strings.Join(f.attrs, " "))
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	if len(m.blockAttrs) > 0 {
+		sb.WriteString("\n")
+		for _, a := range m.blockAttrs {
+			sb.WriteString(fmt.Sprintf("  %s\n", a))
+		}
+	}
+	sb.WriteString("}\n\n")
+	return sb.String()
+}
+
+// prismaIndex renders an index as @@index or @@unique. Partial predicates and
+// INCLUDE columns have no Prisma equivalent.
+func prismaIndex(idx model.Index) string {
+	fields := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		fields[i] = prismaIdentifier(col.Name)
+		if col.IsDescending() {
+			fields[i] += "(sort: Desc)"
+		}
+	}
+
+	args := []string{"[" + strings.Join(fields, ", ") + "]"}
+	if idx.Name != "" {
+		args = append(args, "map: "+quoteJS(idx.Name))
+	}
+	if typ, ok := prismaIndexTypes[strings.ToLower(idx.Method)]; ok && !idx.Unique {
+		args = append(args, "type: "+typ)
+	}
+
+	attr := "@@index"
+	if idx.Unique {
+		attr = "@@unique"
+	}
+	return fmt.Sprintf("%s(%s)", attr, strings.Join(args, ", "))
+}
+
+// prismaUnique renders a UNIQUE constraint as @@unique. Prisma has no CHECK
+// constraints, so those are not exported at all.
+func prismaUnique(u model.Unique) string {
+	fields := make([]string, len(u.Columns))
+	for i, col := range u.Columns {
+		fields[i] = prismaIdentifier(col)
+	}
+	args := "[" + strings.Join(fields, ", ") + "]"
+	if u.Name != "" {
+		args += ", map: " + quoteJS(u.Name)
+	}
+	return fmt.Sprintf("@@unique(%s)", args)
+}
+
+var prismaIndexTypes = map[string]string{
+	"btree":  "BTree",
+	"hash":   "Hash",
+	"gist":   "Gist",
+	"gin":    "Gin",
+	"spgist": "SpGist",
+	"brin":   "Brin",
+}
+
+// prismaNativeType returns the @db attribute that keeps a column's length,
+// precision or scale, which Prisma's scalar types alone cannot express
+func prismaNativeType(col model.Column) string {
+	pgType := mapTypeToPostgres(col.Type)
+	args := typeArgs(familyOf(pgType), col, 0)
+	if args == "" {
+		return ""
+	}
+	switch pgType {
+	case "VARCHAR":
+		return "@db.VarChar" + args
+	case "CHAR":
+		return "@db.Char" + args
+	case "DECIMAL", "NUMERIC":
+		if col.Scale == 0 {
+			// Prisma always takes both arguments
+			return fmt.Sprintf("@db.Decimal(%s, 0)", strings.Trim(args, "()"))
+		}
+		return "@db.Decimal" + strings.Replace(args, ",", ", ", 1)
+	case "TIME":
+		return "@db.Time" + args
+	case "TIMESTAMP":
+		return "@db.Timestamp" + args
+	case "TIMESTAMPTZ":
+		return "@db.Timestamptz" + args
+	}
+	return ""
+}
+
+func mapTypeToPrisma(t string) string {
+	upper := strings.ToUpper(t)
+	switch {
+	case upper == "BIGINT" || upper == "BIGSERIAL":
+		return "BigInt"
+	case upper == "TINYINT(1)" || strings.Contains(upper, "BOOL"):
+		return "Boolean"
+	case strings.Contains(upper, "INT") || upper == "SERIAL" || upper == "YEAR":
+		return "Int"
+	case strings.Contains(upper, "DECIMAL") || strings.Contains(upper, "NUMERIC"):
+		return "Decimal"
+	case strings.Contains(upper, "FLOAT") || strings.Contains(upper, "DOUBLE") || upper == "REAL":
+		return "Float"
+	case upper == "DATE" || upper == "TIME" || strings.Contains(upper, "TIMESTAMP") || upper == "DATETIME":
+		return "DateTime"
+	case upper == "JSON" || upper == "JSONB":
+		return "Json"
+	case strings.Contains(upper, "BLOB") || strings.Contains(upper, "BINARY") || upper == "BYTEA":
+		return "Bytes"
+	default:
+		return "String"
+	}
+}
+
+func mapReferentialActionPrisma(action string) string {
+	switch strings.ToUpper(action) {
+	case "CASCADE":
+		return "Cascade"
+	case "SET NULL":
+		return "SetNull"
+	case "SET DEFAULT":
+		return "SetDefault"
+	case "RESTRICT":
+		return "Restrict"
+	default:
+		return "NoAction"
+	}
+}
+
+func formatDefaultPrisma(def string, colType string, isEnum bool) string {
+	upper := strings.ToUpper(def)
+	if upper == "NOW()" || upper == "CURRENT_TIMESTAMP" {
+		return "now()"
+	}
+	if upper == "GEN_RANDOM_UUID()" || upper == "UUID()" || upper == "UUID_GENERATE_V4()" {
+		return "uuid()"
+	}
+	if isEnum {
+		return prismaIdentifier(def)
+	}
+	switch mapTypeToPrisma(colType) {
+	case "Boolean":
+		if upper == "TRUE" || upper == "1" {
+			return "true"
+		}
+		if upper == "FALSE" || upper == "0" {
+			return "false"
+		}
+	case "Int", "BigInt", "Float", "Decimal":
+		if isNumericLiteral(def) {
+			return def
+		}
+	case "String":
+		return quoteJS(def)
+	}
+	return fmt.Sprintf("dbgenerated(%s)", quoteJS(def))
+}
+
+// prismaIdentifier returns a name that is valid for Prisma models, fields and
+// enum values, which must start with a letter
+func prismaIdentifier(s string) string {
+	ident := strings.TrimLeft(sanitizeIdentifier(s), "_")
+	if ident == "" || !unicode.IsLetter(rune(ident[0])) {
+		ident = "x" + ident
+	}
+	return ident
+}
+
+// prismaRelationFieldName derives the relation field from the FK column,
+// e.g. "author_id" -> "author", falling back to the referenced table name
+func prismaRelationFieldName(column, refTable string) string {
+	lower := strings.ToLower(column)
+	for _, suffix := range []string{"_id", "id"} {
+		if strings.HasSuffix(lower, suffix) && len(column) > len(suffix) {
+			return prismaIdentifier(toCamelCase(column[:len(column)-len(suffix)]))
+		}
+	}
+	return prismaIdentifier(toCamelCase(refTable))
+}
+
+func prismaIdentifierList(names []string) string {
+	idents := make([]string, len(names))
+	for i, name := range names {
+		idents[i] = prismaIdentifier(name)
+	}
+	return strings.Join(idents, ", ")
+}
+
+func prismaPairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "\x00" + b
+}
@@ -0,0 +1,180 @@
+package exporter
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Identifiers and literals come straight from user input, so every name,
+// enum value and default written into a script goes through these helpers
+// rather than being placed between quotes with fmt.Sprintf.
+
+// quoteIdentMySQL quotes a MySQL identifier with backticks
+func quoteIdentMySQL(name string) string {
+	name = strings.ReplaceAll(name, "\x00", "")
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteIdentANSI quotes an identifier with double quotes, as PostgreSQL and
+// SQLite expect
+func quoteIdentANSI(name string) string {
+	name = strings.ReplaceAll(name, "\x00", "")
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteIdentMSSQL quotes a SQL Server identifier with brackets
+func quoteIdentMSSQL(name string) string {
+	name = strings.ReplaceAll(name, "\x00", "")
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// quoteStringMySQL writes a MySQL string literal. Backslashes are escapes in
+// MySQL's default SQL mode, so they are doubled along with quotes.
+func quoteStringMySQL(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `''`, "\x00", `\0`, "\x1a", `\Z`)
+	return "'" + r.Replace(s) + "'"
+}
+
+// quoteStringANSI writes a standard SQL string literal for PostgreSQL and
+// SQLite. PostgreSQL cannot store NUL characters, so they are dropped.
+func quoteStringANSI(s string) string {
+	s = strings.ReplaceAll(s, "\x00", "")
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteStringMSSQL writes a SQL Server Unicode string literal
+func quoteStringMSSQL(s string) string {
+	s = strings.ReplaceAll(s, "\x00", "")
+	return "N'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteJS writes a JavaScript string literal. JSON strings are valid
+// JavaScript, including the escaping of U+2028 and U+2029.
+func quoteJS(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// isNumericLiteral reports whether a default can be written as a bare number.
+// ParseFloat alone would also accept forms like Inf, NaN and hex floats.
+func isNumericLiteral(s string) bool {
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return false
+	}
+	return strings.Trim(s, "0123456789.eE+-") == ""
+}
+
+// mongoCollection returns the shell expression for a collection, using
+// getCollection when the name cannot be written as a property of db
+func mongoCollection(name string) string {
+	if !isJSIdentifier(name) || mongoReserved[name] {
+		return "db.getCollection(" + quoteJS(name) + ")"
+	}
+	return "db." + name
+}
+
+func isJSIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ReservedIn returns the SQL formats in which name is a reserved word and
+// must always be quoted in queries
+func ReservedIn(name string) []ExportFormat {
+	var formats []ExportFormat
+	upper := strings.ToUpper(name)
+	if mysqlReserved[upper] {
+		formats = append(formats, FormatMySQL)
+	}
+	if postgresReserved[upper] {
+		formats = append(formats, FormatPostgres)
+	}
+	return formats
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// mysqlReserved holds the reserved words of MySQL 8.0
+var mysqlReserved = wordSet(`
+	ACCESSIBLE ADD ALL ALTER ANALYZE AND AS ASC ASENSITIVE BEFORE BETWEEN BIGINT
+	BINARY BLOB BOTH BY CALL CASCADE CASE CHANGE CHAR CHARACTER CHECK COLLATE
+	COLUMN CONDITION CONSTRAINT CONTINUE CONVERT CREATE CROSS CUBE CUME_DIST
+	CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP CURRENT_USER CURSOR DATABASE
+	DATABASES DAY_HOUR DAY_MICROSECOND DAY_MINUTE DAY_SECOND DEC DECIMAL DECLARE
+	DEFAULT DELAYED DELETE DENSE_RANK DESC DESCRIBE DETERMINISTIC DISTINCT
+	DISTINCTROW DIV DOUBLE DROP DUAL EACH ELSE ELSEIF EMPTY ENCLOSED ESCAPED
+	EXCEPT EXISTS EXIT EXPLAIN FALSE FETCH FIRST_VALUE FLOAT FLOAT4 FLOAT8 FOR
+	FORCE FOREIGN FROM FULLTEXT FUNCTION GENERATED GET GRANT GROUP GROUPING GROUPS
+	HAVING HIGH_PRIORITY HOUR_MICROSECOND HOUR_MINUTE HOUR_SECOND IF IGNORE IN
+	INDEX INFILE INNER INOUT INSENSITIVE INSERT INT INT1 INT2 INT3 INT4 INT8
+	INTEGER INTERSECT INTERVAL INTO IO_AFTER_GTIDS IO_BEFORE_GTIDS IS ITERATE JOIN
+	JSON_TABLE KEY KEYS KILL LAG LAST_VALUE LATERAL LEAD LEADING LEAVE LEFT LIKE
+	LIMIT LINEAR LINES LOAD LOCALTIME LOCALTIMESTAMP LOCK LONG LONGBLOB LONGTEXT
+	LOOP LOW_PRIORITY MASTER_BIND MASTER_SSL_VERIFY_SERVER_CERT MATCH MAXVALUE
+	MEDIUMBLOB MEDIUMINT MEDIUMTEXT MIDDLEINT MINUTE_MICROSECOND MINUTE_SECOND MOD
+	MODIFIES NATURAL NOT NO_WRITE_TO_BINLOG NTH_VALUE NTILE NULL NUMERIC OF ON
+	OPTIMIZE OPTIMIZER_COSTS OPTION OPTIONALLY OR ORDER OUT OUTER OUTFILE OVER
+	PARTITION PERCENT_RANK PRECISION PRIMARY PROCEDURE PURGE RANGE RANK READ READS
+	READ_WRITE REAL RECURSIVE REFERENCES REGEXP RELEASE RENAME REPEAT REPLACE
+	REQUIRE RESIGNAL RESTRICT RETURN REVOKE RIGHT RLIKE ROW ROWS ROW_NUMBER SCHEMA
+	SCHEMAS SECOND_MICROSECOND SELECT SENSITIVE SEPARATOR SET SHOW SIGNAL SMALLINT
+	SPATIAL SPECIFIC SQL SQLEXCEPTION SQLSTATE SQLWARNING SQL_BIG_RESULT
+	SQL_CALC_FOUND_ROWS SQL_SMALL_RESULT SSL STARTING STORED STRAIGHT_JOIN SYSTEM
+	TABLE TERMINATED THEN TINYBLOB TINYINT TINYTEXT TO TRAILING TRIGGER TRUE UNDO
+	UNION UNIQUE UNLOCK UNSIGNED UPDATE USAGE USE USING UTC_DATE UTC_TIME
+	UTC_TIMESTAMP VALUES VARBINARY VARCHAR VARCHARACTER VARYING VIRTUAL WHEN WHERE
+	WHILE WINDOW WITH WRITE XOR YEAR_MONTH ZEROFILL
+`)
+
+// postgresReserved holds the words PostgreSQL reserves outright, including
+// those that may only be used as function or type names
+var postgresReserved = wordSet(`
+	ALL ANALYSE ANALYZE AND ANY ARRAY AS ASC ASYMMETRIC AUTHORIZATION BINARY BOTH
+	CASE CAST CHECK COLLATE COLLATION COLUMN CONCURRENTLY CONSTRAINT CREATE CROSS
+	CURRENT_CATALOG CURRENT_DATE CURRENT_ROLE CURRENT_SCHEMA CURRENT_TIME
+	CURRENT_TIMESTAMP CURRENT_USER DEFAULT DEFERRABLE DESC DISTINCT DO ELSE END
+	EXCEPT FALSE FETCH FOR FOREIGN FREEZE FROM FULL GRANT GROUP HAVING ILIKE IN
+	INITIALLY INNER INTERSECT INTO IS ISNULL JOIN LATERAL LEADING LEFT LIKE LIMIT
+	LOCALTIME LOCALTIMESTAMP NATURAL NOT NOTNULL NULL OFFSET ON ONLY OR ORDER
+	OUTER OVERLAPS PLACING PRIMARY REFERENCES RETURNING RIGHT SELECT SESSION_USER
+	SIMILAR SOME SYMMETRIC SYSTEM_USER TABLE TABLESAMPLE THEN TO TRAILING TRUE
+	UNION UNIQUE USER USING VARIADIC VERBOSE WHEN WHERE WINDOW WITH
+`)
+
+// mongoReserved holds JavaScript reserved words and the mongo shell's own
+// db methods, neither of which can name a collection as db.<name>
+var mongoReserved = wordSet(`
+	await break case catch class const continue debugger default delete do else
+	enum export extends false finally for function if implements import in
+	instanceof interface let new null package private protected public return
+	static super switch this throw true try typeof var void while with yield
+	adminCommand aggregate auth changeUserPassword commandHelp createCollection
+	createRole createUser createView currentOp dropAllRoles dropAllUsers
+	dropDatabase dropRole dropUser eval fsyncLock fsyncUnlock getCollection
+	getCollectionInfos getCollectionNames getLogComponents getMongo getName
+	getProfilingLevel getProfilingStatus getReplicationInfo getRole getRoles
+	getSiblingDB getUser getUsers grantPrivilegesToRole grantRolesToRole
+	grantRolesToUser help hello hostInfo isMaster killOp listCommands logout
+	printCollectionStats printReplicationInfo printSecondaryReplicationInfo
+	printShardingStatus resetError revokePrivilegesFromRole revokeRolesFromRole
+	revokeRolesFromUser rotateCertificates runCommand serverBuildInfo
+	serverCmdLineOpts serverStatus setLogLevel setProfilingLevel shutdownServer
+	stats updateRole updateUser version watch
+`)
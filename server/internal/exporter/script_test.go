@@ -0,0 +1,466 @@
+package exporter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// scriptDialect describes the lexical rules of a script language, enough to
+// split a generated script into statements the way its engine would
+type scriptDialect struct {
+	format ExportFormat
+	// identQuotes maps the characters opening a quoted identifier to the ones
+	// closing it; a doubled closing character stands for itself
+	identQuotes map[byte]byte
+	// stringQuotes open string literals
+	stringQuotes string
+	// doubledQuotes lets a doubled quote stand for itself inside a string
+	doubledQuotes bool
+	// backslashEscapes makes a backslash escape the next character of a string
+	backslashEscapes bool
+	// nationalStrings allows N'...' literals
+	nationalStrings bool
+	lineComment     string
+	// batchSeparator allows lines holding only GO between statements
+	batchSeparator bool
+	// unicodeLineBreaks ends lines at U+2028 and U+2029 as well, as in
+	// JavaScript
+	unicodeLineBreaks bool
+}
+
+var scriptDialects = []scriptDialect{
+	{format: FormatMySQL, identQuotes: map[byte]byte{'`': '`'}, stringQuotes: `'"`, doubledQuotes: true, backslashEscapes: true, lineComment: "--"},
+	{format: FormatPostgres, identQuotes: map[byte]byte{'"': '"'}, stringQuotes: "'", doubledQuotes: true, lineComment: "--"},
+	{format: FormatSQLite, identQuotes: map[byte]byte{'"': '"', '`': '`'}, stringQuotes: "'", doubledQuotes: true, lineComment: "--"},
+	{format: FormatMSSQL, identQuotes: map[byte]byte{'[': ']', '"': '"'}, stringQuotes: "'", doubledQuotes: true, nationalStrings: true, lineComment: "--", batchSeparator: true},
+	{format: FormatMongo, stringQuotes: `'"`, backslashEscapes: true, lineComment: "//", unicodeLineBreaks: true},
+}
+
+func scriptDialectOf(format ExportFormat) scriptDialect {
+	for _, d := range scriptDialects {
+		if d.format == format {
+			return d
+		}
+	}
+	panic("no script dialect for " + string(format))
+}
+
+type scriptTokenKind int
+
+const (
+	tokenWord scriptTokenKind = iota
+	tokenIdent
+	tokenString
+	tokenPunct
+)
+
+type scriptToken struct {
+	kind scriptTokenKind
+	text string
+}
+
+type scriptStatement []scriptToken
+
+// parseScript splits a script into statements, failing on what its engine
+// would reject before running anything: unterminated literals, unbalanced
+// brackets, block comments, or text after the last statement
+func parseScript(d scriptDialect, src string) ([]scriptStatement, error) {
+	var statements []scriptStatement
+	var current scriptStatement
+	var brackets []byte
+	closing := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	lineStart := true
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		}
+
+		if lineStart && d.batchSeparator && len(current) == 0 {
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			if strings.TrimSpace(src[i:i+end]) == "GO" {
+				i += end
+				continue
+			}
+		}
+		lineStart = false
+
+		switch {
+		case strings.HasPrefix(src[i:], d.lineComment):
+			i += d.lineLength(src[i:])
+
+		case strings.HasPrefix(src[i:], "/*"):
+			return nil, fmt.Errorf("block comment at byte %d", i)
+
+		case d.identQuotes[c] != 0:
+			end, err := scanQuoted(src, i, d.identQuotes[c], true, false)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, scriptToken{tokenIdent, src[i:end]})
+			i = end
+
+		case strings.IndexByte(d.stringQuotes, c) >= 0,
+			d.nationalStrings && c == 'N' && i+1 < len(src) && src[i+1] == '\'':
+			start := i
+			if c == 'N' {
+				i++
+			}
+			end, err := scanQuoted(src, i, src[i], d.doubledQuotes, d.backslashEscapes)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, scriptToken{tokenString, src[start:end]})
+			i = end
+
+		case isScriptWordByte(c):
+			start := i
+			for i < len(src) && isScriptWordByte(src[i]) {
+				i++
+			}
+			current = append(current, scriptToken{tokenWord, src[start:i]})
+
+		case c == '(' || c == '[' || c == '{':
+			brackets = append(brackets, c)
+			current = append(current, scriptToken{tokenPunct, string(c)})
+			i++
+
+		case c == ')' || c == ']' || c == '}':
+			if len(brackets) == 0 || brackets[len(brackets)-1] != closing[c] {
+				return nil, fmt.Errorf("unbalanced %q at byte %d", c, i)
+			}
+			brackets = brackets[:len(brackets)-1]
+			current = append(current, scriptToken{tokenPunct, string(c)})
+			i++
+
+		case c == ';':
+			if len(brackets) > 0 {
+				return nil, fmt.Errorf("statement ends inside brackets at byte %d", i)
+			}
+			if len(current) == 0 {
+				return nil, fmt.Errorf("empty statement at byte %d", i)
+			}
+			statements = append(statements, current)
+			current = nil
+			i++
+
+		default:
+			current = append(current, scriptToken{tokenPunct, string(c)})
+			i++
+		}
+	}
+
+	if len(current) > 0 || len(brackets) > 0 {
+		return nil, fmt.Errorf("unterminated statement at the end of the script")
+	}
+	return statements, nil
+}
+
+// lineLength returns the length of the line src starts with
+func (d scriptDialect) lineLength(src string) int {
+	for i, r := range src {
+		if r == '\n' || r == '\r' || d.unicodeLineBreaks && (r == '\u2028' || r == '\u2029') {
+			return i
+		}
+	}
+	return len(src)
+}
+
+// scanQuoted returns the end of the quoted token opened at src[start]
+func scanQuoted(src string, start int, quote byte, doubled, backslash bool) (int, error) {
+	for i := start + 1; i < len(src); i++ {
+		switch {
+		case backslash && src[i] == '\\':
+			i++
+		case src[i] == quote:
+			if doubled && i+1 < len(src) && src[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		case src[i] == '\n' && !doubled:
+			// JavaScript strings cannot span lines
+			return 0, fmt.Errorf("line break in string at byte %d", i)
+		}
+	}
+	return 0, fmt.Errorf("unterminated %c at byte %d", quote, start)
+}
+
+func isScriptWordByte(c byte) bool {
+	return c == '_' || c == '@' || c == '$' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// scriptWords returns the unquoted words of the statements
+func scriptWords(statements []scriptStatement) map[string]bool {
+	words := make(map[string]bool)
+	for _, s := range statements {
+		for _, t := range s {
+			if t.kind == tokenWord {
+				words[t.text] = true
+			}
+		}
+	}
+	return words
+}
+
+// defaultWords are the words a column default may turn into
+var defaultWords = map[string]bool{
+	"NULL": true, "TRUE": true, "FALSE": true, "NOW": true, "UUID": true, "NEWID": true,
+	"CURRENT_TIMESTAMP": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "LOCALTIMESTAMP": true,
+	"GEN_RANDOM_UUID": true, "UUID_GENERATE_V4": true,
+}
+
+// injectionSchema puts each of the given strings wherever user input reaches
+// a script: table, column, index and constraint names, a default, an enum
+// value, comments and a CHECK name
+func injectionSchema(table, column, def, enumValue, comment, typ string) model.SchemaData {
+	child := table + "_child"
+	return model.SchemaData{Tables: []model.Table{
+		{
+			Name:    table,
+			Comment: comment,
+			Columns: []model.Column{
+				{Name: "id", Type: "INT", PrimaryKey: true, AutoIncrement: true},
+				{Name: column, Type: typ, Default: &def, Comment: comment},
+				{Name: "kind", Type: "ENUM", EnumValues: []string{enumValue, "b"}, Default: &enumValue},
+			},
+			Indexes: []model.Index{{Name: column + "_idx", Columns: []model.IndexColumn{{Name: column}}}},
+			Uniques: []model.Unique{{Name: column + "_key", Columns: []string{column, "kind"}}},
+			Checks:  []model.Check{{Name: column + "_check", Expression: "id % 2 = 0"}},
+		},
+		{
+			Name: child,
+			Columns: []model.Column{
+				{Name: "id", Type: "INT", PrimaryKey: true},
+				{Name: column, Type: "INT"},
+			},
+			ForeignKeys: []model.ForeignKey{{
+				Name:       column + "_fk",
+				Column:     column,
+				References: model.Reference{Table: table, Column: "id"},
+				OnDelete:   "CASCADE",
+			}},
+		},
+	}}
+}
+
+// checkScripts exports the schema in every script format and checks that
+// each script parses into the same statements as one for harmless names,
+// using no words of its own beyond those a default may produce. Input that
+// escaped its quoting would add statements or words.
+func checkScripts(t *testing.T, table, column, def, enumValue, comment, typ string) {
+	t.Helper()
+	baselineComment := ""
+	if comment != "" {
+		baselineComment = "c"
+	}
+	schema := injectionSchema(table, column, def, enumValue, comment, typ)
+	baseline := injectionSchema("t", "c", "x", "a", baselineComment, typ)
+
+	for _, d := range scriptDialects {
+		want, err := Export(baseline, d.format)
+		if err != nil {
+			t.Fatalf("%s: baseline export failed: %v", d.format, err)
+		}
+		wantStatements, err := parseScript(d, want)
+		if err != nil {
+			t.Fatalf("%s: baseline script does not parse: %v\n%s", d.format, err, want)
+		}
+		allowed := scriptWords(wantStatements)
+
+		got, err := Export(schema, d.format)
+		if err != nil {
+			t.Fatalf("%s: export failed: %v", d.format, err)
+		}
+		statements, err := parseScript(d, got)
+		if err != nil {
+			t.Fatalf("%s: script does not parse: %v\n%s", d.format, err, got)
+		}
+		if len(statements) != len(wantStatements) {
+			t.Fatalf("%s: script has %d statements, want %d\n%s", d.format, len(statements), len(wantStatements), got)
+		}
+		for word := range scriptWords(statements) {
+			if allowed[word] || defaultWords[strings.ToUpper(word)] || isNumericLiteral(word) {
+				continue
+			}
+			// Mongo writes collection names that are identifiers unquoted,
+			// and looks up the others with getCollection
+			if d.format == FormatMongo && (word == table || word == table+"_child" || word == "getCollection") {
+				continue
+			}
+			t.Fatalf("%s: unexpected word %q outside quotes\n%s", d.format, word, got)
+		}
+	}
+}
+
+// migrationSchemas returns two versions of a schema whose migration writes
+// each of the given strings into a hint comment: a table rename, a NOT NULL
+// column without a default, a dropped column that may have been renamed, a
+// removed enum value and the expression of a removed unnamed CHECK
+func migrationSchemas(table, column, enumValue string, quoteString func(string) string) (from, to model.SchemaData) {
+	id := model.Column{Name: "id", Type: "INT", PrimaryKey: true}
+	from = model.SchemaData{Tables: []model.Table{
+		{
+			Name: table,
+			Columns: []model.Column{
+				id,
+				{Name: column + "_old", Type: "TEXT"},
+				{Name: "kind", Type: "ENUM", EnumValues: []string{enumValue, "b"}},
+			},
+			Checks: []model.Check{{Expression: "kind <> " + quoteString(enumValue)}},
+		},
+		{Name: table + "_old", Columns: []model.Column{id, {Name: "note", Type: "TEXT"}}},
+	}}
+	to = model.SchemaData{Tables: []model.Table{
+		{
+			Name: table,
+			Columns: []model.Column{
+				id,
+				{Name: column, Type: "TEXT", NotNull: true},
+				{Name: "kind", Type: "ENUM", EnumValues: []string{"b"}},
+			},
+		},
+		{Name: table + "_new", Columns: []model.Column{id, {Name: "note", Type: "TEXT"}}},
+	}}
+	return from, to
+}
+
+// checkMigrations generates migrations in both directions and checks that
+// each script parses into the same statements as one for harmless names,
+// so that nothing written into a hint comment escaped it
+func checkMigrations(t *testing.T, table, column, enumValue string) {
+	t.Helper()
+	for format, quoteString := range map[ExportFormat]func(string) string{
+		FormatPostgres: quoteStringANSI,
+		FormatMySQL:    quoteStringMySQL,
+	} {
+		d := scriptDialectOf(format)
+		parse := func(table, column, enumValue string) (up, down []scriptStatement) {
+			from, to := migrationSchemas(table, column, enumValue, quoteString)
+			migration, err := GenerateMigration(from, to, format)
+			if err != nil {
+				t.Fatalf("%s: migration failed: %v", format, err)
+			}
+			if up, err = parseScript(d, migration.Up); err != nil {
+				t.Fatalf("%s: up does not parse: %v\n%s", format, err, migration.Up)
+			}
+			if down, err = parseScript(d, migration.Down); err != nil {
+				t.Fatalf("%s: down does not parse: %v\n%s", format, err, migration.Down)
+			}
+			return up, down
+		}
+
+		wantUp, wantDown := parse("t", "c", "a")
+		up, down := parse(table, column, enumValue)
+		for _, script := range []struct {
+			name      string
+			got, want []scriptStatement
+		}{{"up", up, wantUp}, {"down", down, wantDown}} {
+			if len(script.got) != len(script.want) {
+				t.Fatalf("%s: %s has %d statements, want %d", format, script.name, len(script.got), len(script.want))
+			}
+			allowed := scriptWords(script.want)
+			for word := range scriptWords(script.got) {
+				if !allowed[word] {
+					t.Fatalf("%s: unexpected word %q outside quotes in %s", format, word, script.name)
+				}
+			}
+		}
+	}
+}
+
+// checkMongoCheckComment exports an unnamed CHECK that Mongo cannot express,
+// which the script names by its expression in a comment, and checks that
+// the script parses into the same statements as for a harmless expression
+func checkMongoCheckComment(t *testing.T, expr string) {
+	t.Helper()
+	export := func(expr string) []scriptStatement {
+		script, err := Export(model.SchemaData{Tables: []model.Table{{
+			Name:    "t",
+			Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}},
+			Checks:  []model.Check{{Expression: "id % 2 = 0 OR " + expr}},
+		}}}, FormatMongo)
+		if err != nil {
+			t.Fatalf("export failed: %v", err)
+		}
+		statements, err := parseScript(scriptDialectOf(FormatMongo), script)
+		if err != nil {
+			t.Fatalf("script does not parse: %v\n%s", err, script)
+		}
+		return statements
+	}
+	if got, want := export(expr), export("id > 5"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("script has statements %v, want %v", got, want)
+	}
+}
+
+func FuzzExportScripts(f *testing.F) {
+	f.Add("users", "name", "O'Brien", "it's", "a comment", "VARCHAR(255)")
+	f.Add("us`er\"s]", "na'me", `x\'; DROP TABLE users; --`, `c\`, "*/ -- it's", "TEXT")
+	f.Add("t]]; DROP TABLE x; --", "c`); DROP TABLE x; --", "');\nGO\nDROP TABLE x;--", "\"});db.dropDatabase();//", "\n--", "DECIMAL(10,2)")
+	f.Add("orders", "total", "1e5", "NULL", "", "DOUBLE PRECISION")
+	f.Add("a b", "c\x00d", "now()", "\x1a", "\\", "TEXT[]")
+	f.Add("t", "c", "x", "a", "", "TEXT); DROP TABLE users; --")
+	f.Add("x\nDROP TABLE users; --", "c\nDROP TABLE x", "x", "a\nDROP TABLE y; --", "", "TEXT")
+	f.Add("t\u2028db.dropDatabase()", "c\rDROP TABLE x", "x", "a\u2029b", "\u2028db.dropDatabase()", "TEXT")
+
+	f.Fuzz(func(t *testing.T, table, column, def, enumValue, comment, typ string) {
+		for _, name := range []string{table, column} {
+			if strings.ReplaceAll(name, "\x00", "") == "" {
+				t.Skip("names cannot be empty")
+			}
+		}
+		switch strings.ToLower(strings.ReplaceAll(column, "\x00", "")) {
+		case "id", "kind":
+			t.Skip("column clashes with the fixed columns")
+		}
+		if strings.ReplaceAll(enumValue, "\x00", "") == "b" {
+			t.Skip("enum values must differ")
+		}
+
+		if !ValidType(typ) {
+			for _, d := range scriptDialects {
+				if _, err := Export(injectionSchema(table, column, def, enumValue, comment, typ), d.format); err == nil {
+					t.Fatalf("%s: type %q was exported", d.format, typ)
+				}
+			}
+			return
+		}
+		checkScripts(t, table, column, def, enumValue, comment, typ)
+		checkMongoCheckComment(t, comment)
+		checkMigrations(t, table, column, enumValue)
+	})
+}
+
+func TestValidType(t *testing.T) {
+	valid := []string{"INT", "VARCHAR(255)", "DECIMAL(10,2)", "DECIMAL(10, 2)", "DOUBLE PRECISION",
+		"TIMESTAMP WITH TIME ZONE", "TINYINT(1)", "TEXT[]", "user_status"}
+	invalid := []string{"", "TEXT); DROP TABLE users; --", "INT -- comment", "VARCHAR(a)", "TEXT'",
+		"INT;", "A B C D E", "ENUM('a')", "INT\nNOT NULL", "1INT"}
+
+	for _, typ := range valid {
+		if !ValidType(typ) {
+			t.Errorf("ValidType(%q) = false, want true", typ)
+		}
+	}
+	for _, typ := range invalid {
+		if ValidType(typ) {
+			t.Errorf("ValidType(%q) = true, want false", typ)
+		}
+	}
+}
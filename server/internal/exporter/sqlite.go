@@ -0,0 +1,141 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func exportSQLite(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("-- SQLite Schema Export\n")
+	sb.WriteString("-- Generated by DB Schema Generator\n\n")
+	sb.WriteString("PRAGMA foreign_keys = ON;\n\n")
+
+	for _, table := range schema.Tables {
+		sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", quoteIdentANSI(table.Name)))
+
+		var columns []string
+		var primaryKeys []string
+
+		// SQLite only supports AUTOINCREMENT on a single INTEGER PRIMARY KEY column,
+		// which has to be declared inline rather than as a table constraint
+		inlinePK := ""
+		pkCount := 0
+		for _, col := range table.Columns {
+			if col.PrimaryKey {
+				pkCount++
+				if col.AutoIncrement {
+					inlinePK = col.Name
+				}
+			}
+		}
+		if pkCount != 1 {
+			inlinePK = ""
+		}
+
+		for _, col := range table.Columns {
+			sqliteType := mapTypeToSQLite(col.Type)
+
+			if col.Name == inlinePK {
+				columns = append(columns, fmt.Sprintf("  %s INTEGER PRIMARY KEY AUTOINCREMENT", quoteIdentANSI(col.Name)))
+				continue
+			}
+
+			colDef := fmt.Sprintf("  %s %s", quoteIdentANSI(col.Name), sqliteType)
+
+			if col.NotNull {
+				colDef += " NOT NULL"
+			}
+
+			if col.Unique && !col.PrimaryKey {
+				colDef += " UNIQUE"
+			}
+
+			if col.Default != nil {
+				colDef += fmt.Sprintf(" DEFAULT %s", formatDefaultSQLite(*col.Default, col.Type))
+			}
+
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				enumVals := make([]string, len(col.EnumValues))
+				for i, v := range col.EnumValues {
+					enumVals[i] = quoteStringANSI(v)
+				}
+				colDef += fmt.Sprintf(" CHECK (%s IN (%s))", quoteIdentANSI(col.Name), strings.Join(enumVals, ", "))
+			}
+
+			columns = append(columns, colDef)
+
+			if col.PrimaryKey {
+				primaryKeys = append(primaryKeys, quoteIdentANSI(col.Name))
+			}
+		}
+
+		if len(primaryKeys) > 0 {
+			columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+		}
+
+		for _, u := range table.Uniques {
+			columns = append(columns, "  "+uniqueClause(u, quoteIdentANSI))
+		}
+
+		for _, ch := range table.Checks {
+			columns = append(columns, "  "+checkClause(ch, quoteIdentANSI))
+		}
+
+		for _, fk := range table.ForeignKeys {
+			columns = append(columns, "  "+foreignKeyANSI(fk))
+		}
+
+		sb.WriteString(strings.Join(columns, ",\n"))
+		sb.WriteString("\n);\n")
+
+		for _, idx := range table.Indexes {
+			sb.WriteString(createIndexSQLite(table.Name, idx) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// mapTypeToSQLite maps a column type onto one of SQLite's type affinities
+func mapTypeToSQLite(t string) string {
+	upper := strings.ToUpper(t)
+	switch {
+	case strings.Contains(upper, "INT") || upper == "SERIAL" || upper == "BIGSERIAL" ||
+		strings.Contains(upper, "BOOL") || upper == "YEAR":
+		return "INTEGER"
+	case strings.Contains(upper, "FLOAT") || strings.Contains(upper, "DOUBLE") || upper == "REAL":
+		return "REAL"
+	case strings.Contains(upper, "DECIMAL") || strings.Contains(upper, "NUMERIC"):
+		return "NUMERIC"
+	case strings.Contains(upper, "BLOB") || strings.Contains(upper, "BINARY") || upper == "BYTEA":
+		return "BLOB"
+	default:
+		// CHAR, VARCHAR, TEXT, UUID, ENUM, SET, JSON and date/time types are stored as TEXT
+		return "TEXT"
+	}
+}
+
+func formatDefaultSQLite(def string, colType string) string {
+	upper := strings.ToUpper(def)
+	if upper == "NULL" || upper == "CURRENT_TIMESTAMP" || upper == "CURRENT_DATE" || upper == "CURRENT_TIME" {
+		return upper
+	}
+	if upper == "NOW()" {
+		return "CURRENT_TIMESTAMP"
+	}
+	if upper == "TRUE" {
+		return "1"
+	}
+	if upper == "FALSE" {
+		return "0"
+	}
+	if isNumericLiteral(def) {
+		return def
+	}
+	return quoteStringANSI(def)
+}
@@ -0,0 +1,118 @@
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// columnTypePattern is the grammar column types must follow: up to four
+// words, then optionally numeric arguments and array brackets, as in
+// "DOUBLE PRECISION", "DECIMAL(10,2)" or "TEXT[]". Types are written into
+// scripts as they are, so anything else could carry SQL of its own.
+var columnTypePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*( [A-Za-z][A-Za-z0-9_]*){0,3}( ?\( ?[0-9]+ ?(, ?[0-9]+ ?)*\))?(\[\])*$`)
+
+// ValidType reports whether t is a column type the exporters can write
+func ValidType(t string) bool {
+	return len(t) <= 64 && columnTypePattern.MatchString(t)
+}
+
+// checkTypes rejects schemas with a column type that is not ValidType. The
+// error leaves out the names, which may hold anything.
+func checkTypes(schema model.SchemaData) error {
+	for i, table := range schema.Tables {
+		for j, col := range table.Columns {
+			if !ValidType(col.Type) {
+				return fmt.Errorf("column %d of table %d has an invalid type", j+1, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// defaultDecimalPrecision is used when a DECIMAL column has a scale but no
+// precision, matching MySQL's own default
+const defaultDecimalPrecision = 10
+
+// typeFamily groups type names by the arguments they take
+type typeFamily int
+
+const (
+	familyOther typeFamily = iota
+	familyVarString
+	familyFixedString
+	familyDecimal
+	familyTemporal
+)
+
+func familyOf(t string) typeFamily {
+	switch strings.ToUpper(t) {
+	case "VARCHAR", "NVARCHAR", "VARBINARY", "CHARACTER VARYING":
+		return familyVarString
+	case "CHAR", "NCHAR", "BINARY", "CHARACTER":
+		return familyFixedString
+	case "DECIMAL", "NUMERIC":
+		return familyDecimal
+	case "TIME", "TIMESTAMP", "TIMESTAMPTZ", "DATETIME", "DATETIME2", "DATETIMEOFFSET":
+		return familyTemporal
+	default:
+		return familyOther
+	}
+}
+
+// typeArgs returns the parenthesized arguments a column's length, precision
+// and scale give to a type of the given family, or "" when there are none.
+// defaultLength is used for variable-length strings that cannot be declared
+// without one.
+func typeArgs(family typeFamily, col model.Column, defaultLength int) string {
+	switch family {
+	case familyVarString:
+		if col.Length > 0 {
+			return fmt.Sprintf("(%d)", col.Length)
+		}
+		if defaultLength > 0 {
+			return fmt.Sprintf("(%d)", defaultLength)
+		}
+	case familyFixedString:
+		if col.Length > 0 {
+			return fmt.Sprintf("(%d)", col.Length)
+		}
+	case familyDecimal:
+		if col.Precision == 0 && col.Scale == 0 {
+			return ""
+		}
+		precision := col.Precision
+		if precision == 0 {
+			precision = defaultDecimalPrecision
+		}
+		if col.Scale > 0 {
+			return fmt.Sprintf("(%d,%d)", precision, col.Scale)
+		}
+		return fmt.Sprintf("(%d)", precision)
+	case familyTemporal:
+		if col.Precision > 0 {
+			return fmt.Sprintf("(%d)", col.Precision)
+		}
+	}
+	return ""
+}
+
+// sizedType appends a column's type arguments to a type name already mapped
+// for the target dialect. Types the dialect mapping spelled out in full, such
+// as CHAR(36) for UUID, are left alone.
+func sizedType(mapped string, col model.Column, defaultLength int) string {
+	if strings.Contains(mapped, "(") {
+		return mapped
+	}
+	return mapped + typeArgs(familyOf(mapped), col, defaultLength)
+}
+
+// isNumericType reports whether UNSIGNED applies to a column type
+func isNumericType(t string) bool {
+	upper := strings.ToUpper(t)
+	return strings.Contains(upper, "INT") || strings.Contains(upper, "DECIMAL") ||
+		strings.Contains(upper, "NUMERIC") || strings.Contains(upper, "FLOAT") ||
+		strings.Contains(upper, "DOUBLE") || upper == "REAL" || strings.Contains(upper, "SERIAL")
+}
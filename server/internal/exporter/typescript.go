@@ -0,0 +1,240 @@
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+var tsIdentifierRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsBrand describes the branded ID type generated for a single-column primary key
+type tsBrand struct {
+	column string
+	name   string
+	base   string
+}
+
+func exportTypeScript(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("// TypeScript Schema Export\n")
+	sb.WriteString("// Generated by DB Schema Generator\n\n")
+
+	brands := collectTSBrands(schema)
+
+	if len(brands) > 0 {
+		sb.WriteString("declare const brand: unique symbol;\n")
+		sb.WriteString("export type Brand<T, B extends string> = T & { readonly [brand]: B };\n\n")
+		for _, table := range schema.Tables {
+			if b, ok := brands[table.Name]; ok {
+				sb.WriteString(fmt.Sprintf("export type %s = Brand<%s, %s>;\n", b.name, b.base, strconv.Quote(b.name)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, table := range schema.Tables {
+		typeName := tsTypeName(table.Name)
+
+		for _, col := range table.Columns {
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				vals := make([]string, len(col.EnumValues))
+				for i, v := range col.EnumValues {
+					vals[i] = quoteJS(v)
+				}
+				sb.WriteString(fmt.Sprintf("export type %s = %s;\n\n", typeName+tsTypeName(col.Name), strings.Join(vals, " | ")))
+			}
+		}
+
+		if table.Comment != "" {
+			sb.WriteString(jsDocComment(table.Comment, ""))
+		}
+		sb.WriteString(fmt.Sprintf("export interface %s {\n", typeName))
+		for _, col := range table.Columns {
+			if col.Comment != "" {
+				sb.WriteString(jsDocComment(col.Comment, "  "))
+			}
+			typ := mapTypeToTypeScript(col.Type)
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				typ = typeName + tsTypeName(col.Name)
+			}
+			if brand := tsColumnBrand(table, col, brands); brand != "" {
+				typ = brand
+			}
+
+			if col.NotNull || col.PrimaryKey {
+				sb.WriteString(fmt.Sprintf("  %s: %s;\n", tsPropertyName(col.Name), typ))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %s?: %s | null;\n", tsPropertyName(col.Name), typ))
+			}
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	return sb.String(), nil
+}
+
+func exportZod(schema model.SchemaData) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("// Zod Schema Export\n")
+	sb.WriteString("// Generated by DB Schema Generator\n\n")
+	sb.WriteString("import { z } from \"zod\";\n\n")
+
+	brands := collectTSBrands(schema)
+
+	// Brands and enums are declared up front so that any object can reference them
+	for _, table := range schema.Tables {
+		if b, ok := brands[table.Name]; ok {
+			for _, col := range table.Columns {
+				if col.Name == b.column {
+					sb.WriteString(fmt.Sprintf("export const %sSchema = %s.brand<%s>();\n", b.name, mapTypeToZod(col.Type), strconv.Quote(b.name)))
+					sb.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %sSchema>;\n\n", b.name, b.name))
+				}
+			}
+		}
+	}
+
+	for _, table := range schema.Tables {
+		typeName := tsTypeName(table.Name)
+		for _, col := range table.Columns {
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				enumName := typeName + tsTypeName(col.Name)
+				vals := make([]string, len(col.EnumValues))
+				for i, v := range col.EnumValues {
+					vals[i] = quoteJS(v)
+				}
+				sb.WriteString(fmt.Sprintf("export const %sSchema = z.enum([%s]);\n", enumName, strings.Join(vals, ", ")))
+				sb.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %sSchema>;\n\n", enumName, enumName))
+			}
+		}
+	}
+
+	for _, table := range schema.Tables {
+		typeName := tsTypeName(table.Name)
+
+		sb.WriteString(fmt.Sprintf("export const %sSchema = z.object({\n", typeName))
+		for _, col := range table.Columns {
+			typ := mapTypeToZod(col.Type)
+			if typ == "z.string()" && col.Length > 0 {
+				typ += fmt.Sprintf(".max(%d)", col.Length)
+			}
+			if col.Unsigned && strings.HasPrefix(typ, "z.number()") {
+				typ += ".nonnegative()"
+			}
+			if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) > 0 {
+				typ = typeName + tsTypeName(col.Name) + "Schema"
+			}
+			if brand := tsColumnBrand(table, col, brands); brand != "" {
+				typ = brand + "Schema"
+			}
+
+			if !col.NotNull && !col.PrimaryKey {
+				typ += ".nullish()"
+			}
+			if col.Comment != "" {
+				typ += fmt.Sprintf(".describe(%s)", quoteJS(col.Comment))
+			}
+			sb.WriteString(fmt.Sprintf("  %s: %s,\n", tsPropertyName(col.Name), typ))
+		}
+		if table.Comment != "" {
+			sb.WriteString(fmt.Sprintf("}).describe(%s);\n", quoteJS(table.Comment)))
+		} else {
+			sb.WriteString("});\n")
+		}
+		sb.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %sSchema>;\n\n", typeName, typeName))
+	}
+
+	return sb.String(), nil
+}
+
+// collectTSBrands returns the branded ID type for every table with a
+// single-column primary key, keyed by table name
+func collectTSBrands(schema model.SchemaData) map[string]tsBrand {
+	brands := make(map[string]tsBrand)
+	for _, table := range schema.Tables {
+		var pks []model.Column
+		for _, col := range table.Columns {
+			if col.PrimaryKey {
+				pks = append(pks, col)
+			}
+		}
+		if len(pks) != 1 {
+			continue
+		}
+		brands[table.Name] = tsBrand{
+			column: pks[0].Name,
+			name:   tsTypeName(table.Name) + tsTypeName(pks[0].Name),
+			base:   mapTypeToTypeScript(pks[0].Type),
+		}
+	}
+	return brands
+}
+
+// tsColumnBrand returns the brand a column should use: its own table's when it
+// is the primary key, or the referenced table's when it is a foreign key to one
+func tsColumnBrand(table model.Table, col model.Column, brands map[string]tsBrand) string {
+	if b, ok := brands[table.Name]; ok && b.column == col.Name {
+		return b.name
+	}
+	for _, fk := range table.ForeignKeys {
+		if fk.IsComposite() || fk.Column != col.Name {
+			continue
+		}
+		if b, ok := brands[fk.References.Table]; ok && b.column == fk.References.Column {
+			return b.name
+		}
+	}
+	return ""
+}
+
+func mapTypeToTypeScript(t string) string {
+	upper := strings.ToUpper(t)
+	switch {
+	case upper == "TINYINT(1)" || strings.Contains(upper, "BOOL"):
+		return "boolean"
+	case strings.Contains(upper, "INT") || upper == "SERIAL" || upper == "BIGSERIAL" || upper == "YEAR" ||
+		strings.Contains(upper, "FLOAT") || strings.Contains(upper, "DOUBLE") || upper == "REAL":
+		return "number"
+	case upper == "JSON" || upper == "JSONB":
+		return "unknown"
+	default:
+		// Decimals, dates and binary data are serialized as strings
+		return "string"
+	}
+}
+
+func mapTypeToZod(t string) string {
+	upper := strings.ToUpper(t)
+	switch {
+	case upper == "TINYINT(1)" || strings.Contains(upper, "BOOL"):
+		return "z.boolean()"
+	case strings.Contains(upper, "INT") || upper == "SERIAL" || upper == "BIGSERIAL" || upper == "YEAR":
+		return "z.number().int()"
+	case strings.Contains(upper, "FLOAT") || strings.Contains(upper, "DOUBLE") || upper == "REAL":
+		return "z.number()"
+	case upper == "UUID":
+		return "z.string().uuid()"
+	case upper == "JSON" || upper == "JSONB":
+		return "z.unknown()"
+	default:
+		return "z.string()"
+	}
+}
+
+// tsTypeName converts a table or column name into a PascalCase type name
+func tsTypeName(s string) string {
+	return sanitizeIdentifier(toPascalCase(s))
+}
+
+// tsPropertyName quotes property names that are not valid identifiers
+func tsPropertyName(s string) string {
+	if tsIdentifierRe.MatchString(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxAPITokenDays caps how far ahead an API token's expiry can be set
+const maxAPITokenDays = 365
+
+type APITokenHandler struct {
+	tokenRepo repository.APITokenRepository
+}
+
+func NewAPITokenHandler(tokenRepo repository.APITokenRepository) *APITokenHandler {
+	return &APITokenHandler{tokenRepo: tokenRepo}
+}
+
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays leaves the token valid indefinitely when omitted
+	ExpiresInDays *int `json:"expires_in_days,omitempty"`
+}
+
+// CreateAPITokenResponse carries the token itself, which is shown only once
+type CreateAPITokenResponse struct {
+	model.APIToken
+	Token string `json:"token"`
+}
+
+// Create issues a new API token for the user
+func (h *APITokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 64 {
+		http.Error(w, `{"error":"name is required and must be at most 64 characters"}`, http.StatusBadRequest)
+		return
+	}
+
+	var scopes model.Scopes
+	for _, scope := range req.Scopes {
+		if !model.Scopes(model.APIScopes).Has(scope) {
+			http.Error(w, `{"error":"unknown scope; use `+strings.Join(model.APIScopes, ", ")+`"}`, http.StatusBadRequest)
+			return
+		}
+		if !scopes.Has(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		http.Error(w, `{"error":"at least one scope is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		days := *req.ExpiresInDays
+		if days < 1 || days > maxAPITokenDays {
+			http.Error(w, `{"error":"expires_in_days must be between 1 and `+strconv.Itoa(maxAPITokenDays)+`"}`, http.StatusBadRequest)
+			return
+		}
+		t := time.Now().AddDate(0, 0, days)
+		expiresAt = &t
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
+		return
+	}
+	token := middleware.APITokenPrefix + secret
+
+	apiToken := model.APIToken{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    token[:len(middleware.APITokenPrefix)+8],
+		TokenHash: hashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := h.tokenRepo.Create(&apiToken); err != nil {
+		http.Error(w, `{"error":"failed to create api token"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPITokenResponse{APIToken: apiToken, Token: token})
+}
+
+// List returns the user's API tokens, without the tokens themselves
+func (h *APITokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := h.tokenRepo.FindByUserID(userID)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch api tokens"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// Delete revokes one of the user's API tokens
+func (h *APITokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error":"invalid api token id"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.tokenRepo.Delete(id, userID); err != nil {
+		http.Error(w, `{"error":"api token not found"}`, http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/go-chi/chi/v5"
+)
+
+// apiTokenRouter mounts the routes API tokens touch behind the same
+// middleware as cmd/main.go
+func apiTokenRouter(auth *AuthHandler, tokens *fakeAPITokenRepo, sessions *fakeSessionRepo, schemas *fakeSchemaRepo) http.Handler {
+	tokenHandler := NewAPITokenHandler(tokens)
+	exportHandler := NewExportHandler(schemas)
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.APITokenAuth(tokens))
+		r.Use(middleware.JWTAuth(auth.jwtSecret, sessions))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireSession)
+
+			r.Get("/auth/sessions", auth.ListSessions)
+			r.Post("/auth/tokens", tokenHandler.Create)
+			r.Get("/auth/tokens", tokenHandler.List)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(model.ScopeExport))
+
+			r.Get("/schemas/{id}/download", exportHandler.DownloadExport)
+		})
+	})
+	return r
+}
+
+func TestAPITokenAccess(t *testing.T) {
+	users := &fakeUserRepo{}
+	sessions := &fakeSessionRepo{}
+	tokens := &fakeAPITokenRepo{}
+	schemas := newFakeSchemaRepo()
+	users.Create(&model.User{Email: "ada@example.com", Name: "ada"})
+	sessions.Create(&model.Session{UserID: 1, Family: "f1", ExpiresAt: time.Now().Add(time.Hour)})
+	schemas.Create(&model.Schema{UserID: 1, Name: "shop", Data: model.SchemaData{Tables: []model.Table{
+		{Name: "products", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+	}}})
+
+	auth := NewAuthHandler(users, sessions, nil, "secret", discardMailer{}, "http://api", "http://client", nil)
+	router := apiTokenRouter(auth, tokens, sessions, schemas)
+	access, err := auth.generateToken(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	create := func(scopes string) string {
+		w := call(http.MethodPost, "/auth/tokens", access, `{"name":"ci","scopes":`+scopes+`}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("creating token: status %d: %s", w.Code, w.Body)
+		}
+		var resp CreateAPITokenResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Token
+	}
+	exportToken := create(`["export"]`)
+	readToken := create(`["schemas:read"]`)
+
+	// Account management is for signed in sessions only
+	for _, path := range []string{"/auth/tokens", "/auth/sessions"} {
+		if w := call(http.MethodGet, path, exportToken, ""); w.Code != http.StatusForbidden {
+			t.Errorf("GET %s with an api token: status %d, want %d", path, w.Code, http.StatusForbidden)
+		}
+		if w := call(http.MethodGet, path, access, ""); w.Code != http.StatusOK {
+			t.Errorf("GET %s with a session: status %d, want %d", path, w.Code, http.StatusOK)
+		}
+	}
+	if w := call(http.MethodPost, "/auth/tokens", exportToken, `{"name":"more","scopes":["export"]}`); w.Code != http.StatusForbidden {
+		t.Errorf("api token created another token: status %d", w.Code)
+	}
+
+	w := call(http.MethodGet, "/schemas/1/download?format=postgres", exportToken, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("download with an export token: status %d: %s", w.Code, w.Body)
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "attachment") || !strings.Contains(w.Body.String(), "CREATE TABLE") {
+		t.Errorf("download did not return the script: %q %s", w.Header().Get("Content-Disposition"), w.Body)
+	}
+
+	if w := call(http.MethodGet, "/schemas/1/download", readToken, ""); w.Code != http.StatusForbidden {
+		t.Errorf("download with a read token: status %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
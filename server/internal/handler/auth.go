@@ -3,24 +3,38 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"time"
+	"strings"
 
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/mailer"
 	"github.com/Dragodui/db-schemas-generator/internal/middleware"
 	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/oidc"
 	"github.com/Dragodui/db-schemas-generator/internal/repository"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	userRepo  repository.UserRepository
-	jwtSecret string
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	identityRepo repository.IdentityRepository
+	jwtSecret    string
+	mailer       mailer.Mailer
+	apiURL       string
+	clientURL    string
+	providers    []*oidc.Provider
 }
 
-func NewAuthHandler(userRepo repository.UserRepository, jwtSecret string) *AuthHandler {
+func NewAuthHandler(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, identityRepo repository.IdentityRepository, jwtSecret string, mail mailer.Mailer, apiURL, clientURL string, providers []*oidc.Provider) *AuthHandler {
 	return &AuthHandler{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		identityRepo: identityRepo,
+		jwtSecret:    jwtSecret,
+		mailer:       mail,
+		apiURL:       strings.TrimSuffix(apiURL, "/"),
+		clientURL:    strings.TrimSuffix(clientURL, "/"),
+		providers:    providers,
 	}
 }
 
@@ -35,9 +49,13 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// AuthResponse carries a short-lived access token and the refresh token that
+// renews it. ExpiresIn is the access token's lifetime in seconds.
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  *model.User `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int         `json:"expires_in"`
+	User         *model.User `json:"user"`
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -75,7 +93,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.generateToken(user.ID)
+	// The account works without it; the user can ask for another link
+	if err := h.sendVerification(user); err != nil {
+		logger.Error.Printf("sending verification email to user %d: %v", user.ID, err)
+	}
+
+	response, err := h.startSession(r, user)
 	if err != nil {
 		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
 		return
@@ -83,7 +106,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(AuthResponse{Token: token, User: user})
+	json.NewEncoder(w).Encode(response)
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -109,14 +132,14 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.generateToken(user.ID)
+	response, err := h.startSession(r, user)
 	if err != nil {
 		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(AuthResponse{Token: token, User: user})
+	json.NewEncoder(w).Encode(response)
 }
 
 func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
@@ -135,14 +158,3 @@ func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
-
-func (h *AuthHandler) generateToken(userID int) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(24 * 7 * time.Hour).Unix(),
-		"iat":     time.Now().Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(h.jwtSecret))
-}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Dragodui/db-schemas-generator/internal/diff"
+)
+
+type DiffResponse struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []diff.Change `json:"changes"`
+}
+
+// Diff compares two versions of a schema given by the from and to query
+// parameters. With format=text the changes are returned as plain text.
+func (h *VersionHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	schema, ok := loadSchema(w, r, h.schemaRepo)
+	if !ok {
+		return
+	}
+
+	from, ok := h.loadVersion(w, schema.ID, r.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := h.loadVersion(w, schema.ID, r.URL.Query().Get("to"))
+	if !ok {
+		return
+	}
+
+	changes := diff.Compare(from.Data, to.Data)
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DiffResponse{From: from.Version, To: to.Version, Changes: changes})
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(diff.Text(changes)))
+	default:
+		http.Error(w, `{"error":"unsupported format"}`, http.StatusBadRequest)
+	}
+}
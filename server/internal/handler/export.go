@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Dragodui/db-schemas-generator/internal/exporter"
 	"github.com/Dragodui/db-schemas-generator/internal/middleware"
@@ -21,8 +22,11 @@ func NewExportHandler(schemaRepo repository.SchemaRepository) *ExportHandler {
 }
 
 type ExportRequest struct {
-	Data   model.SchemaData `json:"data"`
-	Format string           `json:"format"`
+	Data      model.SchemaData `json:"data"`
+	Format    string           `json:"format"`
+	Package   string           `json:"package,omitempty"`
+	Tags      []string         `json:"tags,omitempty"`
+	NullTypes bool             `json:"nullTypes,omitempty"`
 }
 
 type ExportResponse struct {
@@ -61,9 +65,9 @@ func (h *ExportHandler) ExportSchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sql, err := exporter.Export(schema.Data, exporter.ExportFormat(format))
+	sql, err := exporter.ExportWithOptions(schema.Data, exporter.ExportFormat(format), exportOptions(r))
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusBadRequest)
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -83,9 +87,10 @@ func (h *ExportHandler) ExportDirect(w http.ResponseWriter, r *http.Request) {
 		req.Format = "postgres"
 	}
 
-	sql, err := exporter.Export(req.Data, exporter.ExportFormat(req.Format))
+	opts := exporter.Options{Package: req.Package, Tags: req.Tags, NullTypes: req.NullTypes}
+	sql, err := exporter.ExportWithOptions(req.Data, exporter.ExportFormat(req.Format), opts)
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusBadRequest)
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -124,15 +129,28 @@ func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sql, err := exporter.Export(schema.Data, exporter.ExportFormat(format))
+	sql, err := exporter.ExportWithOptions(schema.Data, exporter.ExportFormat(format), exportOptions(r))
 	if err != nil {
-		http.Error(w, `{"error":"`+err.Error()+`"}`, http.StatusBadRequest)
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	ext := "sql"
-	if format == "mongo" {
+	switch format {
+	case "mongo":
 		ext = "js"
+	case "prisma":
+		ext = "prisma"
+	case "go":
+		ext = "go"
+	case "typescript", "zod":
+		ext = "ts"
+	case "mermaid":
+		ext = "mmd"
+	case "plantuml":
+		ext = "puml"
+	case "dbml":
+		ext = "dbml"
 	}
 
 	filename := schema.Name + "_" + format + "." + ext
@@ -140,3 +158,16 @@ func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
 	w.Write([]byte(sql))
 }
+
+// exportOptions reads the optional exporter settings from the query string
+func exportOptions(r *http.Request) exporter.Options {
+	q := r.URL.Query()
+	opts := exporter.Options{
+		Package:   q.Get("package"),
+		NullTypes: q.Get("null_types") == "true",
+	}
+	if tags := q.Get("tags"); tags != "" {
+		opts.Tags = strings.Split(tags, ",")
+	}
+	return opts
+}
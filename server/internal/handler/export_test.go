@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestExportErrorIsJSON(t *testing.T) {
+	repo := newFakeSchemaRepo()
+	repo.Create(&model.Schema{UserID: 1, Name: "shop", Data: model.SchemaData{Tables: []model.Table{
+		{Name: "products", Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}},
+	}}})
+
+	h := NewExportHandler(repo)
+	r := chi.NewRouter()
+	r.Get("/schemas/{id}/export", h.ExportSchema)
+	r.Get("/schemas/{id}/download", h.DownloadExport)
+
+	hostile := url.QueryEscape(`x"}, "admin": true, "y":"`)
+	for _, path := range []string{
+		"/schemas/1/export?format=go&tags=" + hostile,
+		"/schemas/1/download?format=go&tags=" + hostile,
+		"/schemas/1/export?format=" + hostile,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, asUser(httptest.NewRequest(http.MethodGet, path, nil), 1))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d", path, w.Code, http.StatusBadRequest)
+			continue
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: error body is not JSON: %v: %s", path, err, w.Body)
+			continue
+		}
+		if len(body) != 1 || body["error"] == nil {
+			t.Errorf("%s: error body = %v, want only an error", path, body)
+		}
+	}
+}
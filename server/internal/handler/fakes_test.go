@@ -0,0 +1,413 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/mailer"
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(os.DevNull)
+	os.Exit(m.Run())
+}
+
+// In-memory stand-ins for the repositories, enough for the handlers under
+// test
+
+type fakeSchemaRepo struct {
+	mu       sync.Mutex
+	schemas  map[int]*model.Schema
+	versions map[int][]model.SchemaVersion
+}
+
+func newFakeSchemaRepo() *fakeSchemaRepo {
+	return &fakeSchemaRepo{schemas: map[int]*model.Schema{}, versions: map[int][]model.SchemaVersion{}}
+}
+
+func (f *fakeSchemaRepo) Create(s *model.Schema) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s.ID = len(f.schemas) + 1
+	copied := *s
+	f.schemas[s.ID] = &copied
+	f.versions[s.ID] = append(f.versions[s.ID], model.SchemaVersion{SchemaID: s.ID, Version: 1, AuthorID: s.UserID, Name: s.Name, Data: s.Data})
+	return nil
+}
+
+func (f *fakeSchemaRepo) FindByID(id int) (*model.Schema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.schemas[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (f *fakeSchemaRepo) FindByUserID(userID int) ([]model.Schema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []model.Schema
+	for _, s := range f.schemas {
+		if s.UserID == userID {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSchemaRepo) FindPublic() ([]model.Schema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []model.Schema
+	for _, s := range f.schemas {
+		if s.IsPublic {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSchemaRepo) Update(s *model.Schema) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *s
+	f.schemas[s.ID] = &copied
+	return nil
+}
+
+func (f *fakeSchemaRepo) UpdateWithVersion(s *model.Schema, v *model.SchemaVersion) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *s
+	f.schemas[s.ID] = &copied
+	v.SchemaID, v.Version, v.Name, v.Data = s.ID, len(f.versions[s.ID])+1, s.Name, s.Data
+	f.versions[s.ID] = append(f.versions[s.ID], *v)
+	return nil
+}
+
+func (f *fakeSchemaRepo) Delete(id int, userID int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.schemas[id]; !ok || s.UserID != userID {
+		return errors.New("schema not found or unauthorized")
+	}
+	delete(f.schemas, id)
+	delete(f.versions, id)
+	return nil
+}
+
+func (f *fakeSchemaRepo) FindBySchemaID(schemaID int) ([]model.SchemaVersion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	versions := f.versions[schemaID]
+	out := make([]model.SchemaVersion, len(versions))
+	for i, v := range versions {
+		out[len(versions)-1-i] = v
+	}
+	return out, nil
+}
+
+func (f *fakeSchemaRepo) FindByVersion(schemaID int, version int) (*model.SchemaVersion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, v := range f.versions[schemaID] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+// asUser returns the request as made by a signed in user
+func asUser(r *http.Request, userID int) *http.Request {
+	ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID)
+	return r.WithContext(ctx)
+}
+
+type fakeUserRepo struct {
+	mu    sync.Mutex
+	users []*model.User
+}
+
+func (f *fakeUserRepo) Create(u *model.User) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, x := range f.users {
+		if x.Email == u.Email {
+			return errors.New("duplicate email")
+		}
+	}
+	u.ID = len(f.users) + 1
+	f.users = append(f.users, u)
+	return nil
+}
+
+func (f *fakeUserRepo) find(match func(u *model.User) bool) *model.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if match(u) {
+			copied := *u
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (f *fakeUserRepo) FindByID(id int) (*model.User, error) {
+	return f.find(func(u *model.User) bool { return u.ID == id }), nil
+}
+
+func (f *fakeUserRepo) FindByName(name string) (*model.User, error) {
+	return f.find(func(u *model.User) bool { return u.Name == name }), nil
+}
+
+func (f *fakeUserRepo) FindByEmail(email string) (*model.User, error) {
+	return f.find(func(u *model.User) bool { return u.Email == email }), nil
+}
+
+func (f *fakeUserRepo) SetVerifyToken(email, token string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.Email == email {
+			u.VerifyToken, u.VerifyExpiresAt = &token, &expiresAt
+		}
+	}
+	return nil
+}
+
+func (f *fakeUserRepo) VerifyEmail(token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.VerifyToken != nil && *u.VerifyToken == token && u.VerifyExpiresAt.After(time.Now()) {
+			u.EmailVerified, u.VerifyToken, u.VerifyExpiresAt = true, nil, nil
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (f *fakeUserRepo) ResetPassword(token, newHash string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.ResetToken != nil && *u.ResetToken == token && u.ResetExpiresAt.After(time.Now()) {
+			u.PasswordHash, u.ResetToken, u.ResetExpiresAt = newHash, nil, nil
+			return u.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeUserRepo) UpdatePassword(userID int, newHash string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.ID == userID {
+			u.PasswordHash, u.ResetToken, u.ResetExpiresAt = newHash, nil, nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeUserRepo) SetResetToken(email, token string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.Email == email {
+			u.ResetToken, u.ResetExpiresAt = &token, &expiresAt
+		}
+	}
+	return nil
+}
+
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions []*model.Session
+}
+
+func (f *fakeSessionRepo) Create(s *model.Session) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s.ID = len(f.sessions) + 1
+	copied := *s
+	f.sessions = append(f.sessions, &copied)
+	return nil
+}
+
+func (f *fakeSessionRepo) find(match func(s *model.Session) bool) *model.Session {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.sessions {
+		if match(s) {
+			copied := *s
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessionRepo) FindByID(id int) (*model.Session, error) {
+	return f.find(func(s *model.Session) bool { return s.ID == id }), nil
+}
+
+func (f *fakeSessionRepo) FindByFamily(family string) (*model.Session, error) {
+	return f.find(func(s *model.Session) bool { return s.Family == family }), nil
+}
+
+func (f *fakeSessionRepo) FindActiveByUserID(userID int) ([]model.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []model.Session
+	for _, s := range f.sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.ExpiresAt.After(time.Now()) {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSessionRepo) Rotate(id int, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.sessions {
+		if s.ID == id && s.RefreshHash == oldHash {
+			s.RefreshHash, s.ExpiresAt, s.LastUsedAt = newHash, expiresAt, time.Now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeSessionRepo) Revoke(id int) error {
+	return f.revoke(func(s *model.Session) bool { return s.ID == id })
+}
+
+func (f *fakeSessionRepo) RevokeAllForUser(userID int) error {
+	return f.revoke(func(s *model.Session) bool { return s.UserID == userID })
+}
+
+func (f *fakeSessionRepo) revoke(match func(s *model.Session) bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for _, s := range f.sessions {
+		if match(s) && s.RevokedAt == nil {
+			s.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// discardMailer drops every message
+type discardMailer struct{}
+
+func (discardMailer) Send(mailer.Message) error { return nil }
+
+type fakeAPITokenRepo struct {
+	mu     sync.Mutex
+	tokens []*model.APIToken
+}
+
+func (f *fakeAPITokenRepo) Create(t *model.APIToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t.ID = len(f.tokens) + 1
+	copied := *t
+	f.tokens = append(f.tokens, &copied)
+	return nil
+}
+
+func (f *fakeAPITokenRepo) FindByHash(hash string) (*model.APIToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeAPITokenRepo) FindByUserID(userID int) ([]model.APIToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []model.APIToken
+	for i := len(f.tokens) - 1; i >= 0; i-- {
+		if f.tokens[i].UserID == userID {
+			out = append(out, *f.tokens[i])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAPITokenRepo) Touch(id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for _, t := range f.tokens {
+		if t.ID == id {
+			t.LastUsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakeAPITokenRepo) Delete(id, userID int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, t := range f.tokens {
+		if t.ID == id && t.UserID == userID {
+			f.tokens = append(f.tokens[:i], f.tokens[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("api token not found")
+}
+
+type fakeIdentityRepo struct {
+	mu         sync.Mutex
+	users      *fakeUserRepo
+	identities []model.Identity
+}
+
+func (f *fakeIdentityRepo) Create(i *model.Identity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i.ID = len(f.identities) + 1
+	f.identities = append(f.identities, *i)
+	return nil
+}
+
+func (f *fakeIdentityRepo) CreateWithUser(u *model.User, i *model.Identity) error {
+	if err := f.users.Create(u); err != nil {
+		return err
+	}
+	i.UserID = u.ID
+	return f.Create(i)
+}
+
+func (f *fakeIdentityRepo) FindBySubject(provider, subject string) (*model.Identity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, i := range f.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return &i, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/importer"
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/repository"
+)
+
+// maxImportSize caps the size of uploaded scripts
+const maxImportSize = 10 << 20
+
+type ImportHandler struct {
+	schemaRepo repository.SchemaRepository
+	publish    *PublishPolicy
+}
+
+func NewImportHandler(schemaRepo repository.SchemaRepository, publish *PublishPolicy) *ImportHandler {
+	return &ImportHandler{schemaRepo: schemaRepo, publish: publish}
+}
+
+type ImportRequest struct {
+	SQL      string `json:"sql"`
+	Format   string `json:"format"`
+	Name     string `json:"name,omitempty"`
+	IsPublic bool   `json:"is_public,omitempty"`
+}
+
+type ImportResponse struct {
+	Data     model.SchemaData `json:"data"`
+	Format   string           `json:"format"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+type ImportSaveResponse struct {
+	Schema   *model.Schema `json:"schema"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// Import parses a script into schema data without saving it. The script is
+// sent either as JSON or as the raw request body, with the format in the query
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeImportRequest(w, r)
+	if err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Format == "" {
+		http.Error(w, `{"error":"format is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := importer.Import(req.SQL, importer.ImportFormat(req.Format))
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ImportResponse{Data: result.Data, Format: req.Format, Warnings: result.Warnings})
+}
+
+// ImportAndSave parses a script and stores the result as a new schema owned
+// by the current user. The schema must pass validation, as on Create.
+func (h *ImportHandler) ImportAndSave(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	req, err := decodeImportRequest(w, r)
+	if err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Format == "" {
+		http.Error(w, `{"error":"format is required"}`, http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, `{"error":"name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.IsPublic && !h.publish.allow(w, userID) {
+		return
+	}
+
+	result, err := importer.Import(req.SQL, importer.ImportFormat(req.Format))
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if rejectInvalidSchema(w, result.Data) {
+		return
+	}
+
+	schema := &model.Schema{
+		UserID:   userID,
+		Name:     req.Name,
+		Data:     result.Data,
+		IsPublic: req.IsPublic,
+	}
+
+	if err := h.schemaRepo.Create(schema); err != nil {
+		http.Error(w, `{"error":"failed to create schema"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ImportSaveResponse{Schema: schema, Warnings: result.Warnings})
+}
+
+func decodeImportRequest(w http.ResponseWriter, r *http.Request) (*ImportRequest, error) {
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var req ImportRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return nil, err
+		}
+	} else {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		req.SQL = string(raw)
+	}
+
+	q := r.URL.Query()
+	if format := q.Get("format"); format != "" {
+		req.Format = format
+	}
+	if name := q.Get("name"); name != "" {
+		req.Name = name
+	}
+	if q.Get("is_public") == "true" {
+		req.IsPublic = true
+	}
+	return &req, nil
+}
+
+// writeJSONError writes an error message that may contain characters needing
+// escaping, such as quotes from user input
+func writeJSONError(w http.ResponseWriter, msg string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
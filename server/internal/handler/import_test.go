@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImportAndSaveValidates(t *testing.T) {
+	schemas := newFakeSchemaRepo()
+	h := NewImportHandler(schemas, NewPublishPolicy(&fakeUserRepo{}, false))
+
+	importSQL := func(sql string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/schemas/import?format=postgres&name=shop", strings.NewReader(sql))
+		w := httptest.NewRecorder()
+		h.ImportAndSave(w, asUser(req, 1))
+		return w
+	}
+
+	w := importSQL("CREATE TABLE users (id int PRIMARY KEY, email text, email text);")
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("duplicate columns: status %d, want %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body)
+	}
+	var body ValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Diagnostics) == 0 {
+		t.Errorf("response lacks diagnostics: %v: %s", err, w.Body)
+	}
+	if saved, _ := schemas.FindByUserID(1); len(saved) != 0 {
+		t.Errorf("invalid schema was saved: %+v", saved)
+	}
+
+	if w := importSQL("CREATE TABLE users (id int PRIMARY KEY, email text);"); w.Code != http.StatusCreated {
+		t.Errorf("valid schema: status %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Dragodui/db-schemas-generator/internal/exporter"
+)
+
+type MigrationResponse struct {
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Format string `json:"format"`
+	Up     string `json:"up"`
+	Down   string `json:"down"`
+}
+
+// Migration generates up and down scripts between two versions of a schema,
+// given by the from and to query parameters
+func (h *VersionHandler) Migration(w http.ResponseWriter, r *http.Request) {
+	schema, ok := loadSchema(w, r, h.schemaRepo)
+	if !ok {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "postgres"
+	}
+
+	from, ok := h.loadVersion(w, schema.ID, r.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := h.loadVersion(w, schema.ID, r.URL.Query().Get("to"))
+	if !ok {
+		return
+	}
+
+	migration, err := exporter.GenerateMigration(from.Data, to.Data, exporter.ExportFormat(format))
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(MigrationResponse{
+		From:   from.Version,
+		To:     to.Version,
+		Format: format,
+		Up:     migration.Up,
+		Down:   migration.Down,
+	})
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/oidc"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// oidcLoginTTL is how long the user has to sign in at the provider
+	oidcLoginTTL = 10 * time.Minute
+	// oidcCookie holds the state of a login in progress, signed so that it
+	// cannot be forged
+	oidcCookie   = "oidc_login"
+	oidcAudience = "oidc-login"
+)
+
+var (
+	errOIDCEmailUnverified = errors.New("sso_email_unverified")
+	errOIDCLinkUnverified  = errors.New("sso_link_unverified")
+)
+
+type OIDCProviderResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type oidcLoginClaims struct {
+	jwt.RegisteredClaims
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// OIDCProviders lists the single sign-on providers for the login page
+func (h *AuthHandler) OIDCProviders(w http.ResponseWriter, r *http.Request) {
+	response := make([]OIDCProviderResponse, len(h.providers))
+	for i, p := range h.providers {
+		response[i] = OIDCProviderResponse{ID: p.ID, Name: p.Name}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// OIDCLogin sends the browser to the provider to sign in
+func (h *AuthHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	provider := h.provider(chi.URLParam(r, "provider"))
+	if provider == nil {
+		http.Error(w, `{"error":"unknown provider"}`, http.StatusNotFound)
+		return
+	}
+
+	state, err1 := randomToken()
+	nonce, err2 := randomToken()
+	verifier, err3 := randomToken()
+	if err := errors.Join(err1, err2, err3); err != nil {
+		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(r.Context(), h.oidcRedirectURI(provider), state, nonce, verifier)
+	if err != nil {
+		logger.Error.Printf("starting login with %s: %v", provider.ID, err)
+		http.Error(w, `{"error":"provider unavailable"}`, http.StatusBadGateway)
+		return
+	}
+
+	now := time.Now()
+	cookie, err := jwt.NewWithClaims(jwt.SigningMethodHS256, oidcLoginClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcLoginTTL)),
+		},
+		Provider: provider.ID,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+	}).SignedString([]byte(h.jwtSecret))
+	if err != nil {
+		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
+		return
+	}
+
+	h.setOIDCCookie(w, cookie, int(oidcLoginTTL.Seconds()))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallback finishes a login at the provider. The user is found by the
+// provider account, or else linked by verified email address, or else
+// created. The browser is sent back to the client with the session tokens
+// in the URL fragment, which is never sent to a server.
+func (h *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := h.provider(chi.URLParam(r, "provider"))
+	if provider == nil {
+		http.Error(w, `{"error":"unknown provider"}`, http.StatusNotFound)
+		return
+	}
+
+	// The login state works once
+	cookie, err := r.Cookie(oidcCookie)
+	h.setOIDCCookie(w, "", -1)
+	if err != nil {
+		h.oidcFailed(w, r, "sso_failed")
+		return
+	}
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		logger.Warn.Printf("login with %s was refused: %s %s", provider.ID, providerErr, query.Get("error_description"))
+		h.oidcFailed(w, r, "sso_cancelled")
+		return
+	}
+
+	var login oidcLoginClaims
+	_, err = jwt.ParseWithClaims(cookie.Value, &login, func(t *jwt.Token) (interface{}, error) {
+		return []byte(h.jwtSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithAudience(oidcAudience), jwt.WithExpirationRequired())
+	if err != nil || login.Provider != provider.ID ||
+		subtle.ConstantTimeCompare([]byte(login.State), []byte(query.Get("state"))) != 1 {
+		h.oidcFailed(w, r, "sso_failed")
+		return
+	}
+
+	claims, err := provider.Exchange(r.Context(), h.oidcRedirectURI(provider), query.Get("code"), login.Verifier, login.Nonce)
+	if err != nil {
+		logger.Warn.Printf("login with %s: %v", provider.ID, err)
+		h.oidcFailed(w, r, "sso_failed")
+		return
+	}
+
+	user, err := h.oidcUser(provider.ID, claims)
+	if err != nil {
+		logger.Warn.Printf("login with %s as %q: %v", provider.ID, claims.Subject, err)
+		code := "sso_failed"
+		if errors.Is(err, errOIDCEmailUnverified) || errors.Is(err, errOIDCLinkUnverified) {
+			code = err.Error()
+		}
+		h.oidcFailed(w, r, code)
+		return
+	}
+
+	response, err := h.startSession(r, user)
+	if err != nil {
+		logger.Error.Printf("starting session for user %d: %v", user.ID, err)
+		h.oidcFailed(w, r, "sso_failed")
+		return
+	}
+
+	fragment := url.Values{
+		"token":         {response.Token},
+		"refresh_token": {response.RefreshToken},
+		"expires_in":    {strconv.Itoa(response.ExpiresIn)},
+	}
+	http.Redirect(w, r, h.clientURL+"/auth/callback#"+fragment.Encode(), http.StatusFound)
+}
+
+// oidcUser returns the user signing in with the provider account, linking or
+// creating one on first login. Accounts are only linked by an email address
+// both sides have verified; otherwise whoever registered an address first
+// could take over the account of its real owner.
+func (h *AuthHandler) oidcUser(providerID string, claims *oidc.Claims) (*model.User, error) {
+	identity, err := h.identityRepo.FindBySubject(providerID, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := h.userRepo.FindByID(identity.UserID)
+		if err == nil && user == nil {
+			err = errors.New("linked user no longer exists")
+		}
+		return user, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errOIDCEmailUnverified
+	}
+	identity = &model.Identity{Provider: providerID, Subject: claims.Subject, Email: claims.Email}
+
+	user, err := h.userRepo.FindByEmail(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if !user.EmailVerified {
+			return nil, errOIDCLinkUnverified
+		}
+		identity.UserID = user.ID
+		if err := h.identityRepo.Create(identity); err != nil {
+			return nil, err
+		}
+		logger.Info.Printf("linked %s account %q to user %d", providerID, claims.Subject, user.ID)
+		return user, nil
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+	// Without a password the user signs in through the provider, or sets one
+	// with a password reset
+	user = &model.User{
+		Name:          truncate(name, 64),
+		Email:         claims.Email,
+		EmailVerified: true,
+	}
+	if err := h.identityRepo.CreateWithUser(user, identity); err != nil {
+		return nil, err
+	}
+	logger.Info.Printf("created user %d for %s account %q", user.ID, providerID, claims.Subject)
+	return user, nil
+}
+
+func (h *AuthHandler) provider(id string) *oidc.Provider {
+	for _, p := range h.providers {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (h *AuthHandler) oidcRedirectURI(p *oidc.Provider) string {
+	return h.apiURL + "/api/auth/oidc/" + url.PathEscape(p.ID) + "/callback"
+}
+
+func (h *AuthHandler) setOIDCCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		Path:     "/api/auth/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.apiURL, "https://"),
+		// Lax lets the cookie come along on the provider's redirect back
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// oidcFailed sends the browser back to the client's login page with an
+// error code to show
+func (h *AuthHandler) oidcFailed(w http.ResponseWriter, r *http.Request, code string) {
+	http.Redirect(w, r, h.clientURL+"/login?error="+url.QueryEscape(code), http.StatusFound)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/oidc"
+	"github.com/Dragodui/db-schemas-generator/internal/oidc/oidctest"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testAPIURL    = "http://api.test"
+	testClientURL = "http://client.test"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	srv := oidctest.NewServer()
+	defer srv.Close()
+
+	users := &fakeUserRepo{}
+	users.Create(&model.User{Email: "verified@example.com", Name: "verified", EmailVerified: true})
+	users.Create(&model.User{Email: "unverified@example.com", Name: "unverified"})
+	identities := &fakeIdentityRepo{users: users}
+	sessions := &fakeSessionRepo{}
+
+	provider := oidc.NewProvider(srv.Provider("corp"))
+	h := NewAuthHandler(users, sessions, identities, "secret", discardMailer{}, testAPIURL, testClientURL, []*oidc.Provider{provider})
+	r := chi.NewRouter()
+	r.Get("/api/auth/oidc/{provider}/login", h.OIDCLogin)
+	r.Get("/api/auth/oidc/{provider}/callback", h.OIDCCallback)
+
+	// signIn goes through the login as the browser would and returns where
+	// the client is sent at the end
+	signIn := func(claims jwt.MapClaims) *url.URL {
+		t.Helper()
+		srv.Claims = claims
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/corp/login", nil))
+		if w.Code != http.StatusFound {
+			t.Fatalf("login: status %d: %s", w.Code, w.Body)
+		}
+		cookies := w.Result().Cookies()
+
+		back, err := srv.Authorize(w.Header().Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(back, testAPIURL+"/api/auth/oidc/corp/callback?") {
+			t.Fatalf("provider redirected to %s", back)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, strings.TrimPrefix(back, testAPIURL), nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusFound {
+			t.Fatalf("callback: status %d: %s", w.Code, w.Body)
+		}
+		u, err := url.Parse(w.Header().Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+
+	signedInAs := func(u *url.URL) int {
+		t.Helper()
+		if u.Path != "/auth/callback" {
+			t.Fatalf("sent to %s, want /auth/callback", u)
+		}
+		fragment, _ := url.ParseQuery(u.Fragment)
+		if fragment.Get("token") == "" || fragment.Get("refresh_token") == "" {
+			t.Fatalf("callback fragment lacks tokens: %q", u.Fragment)
+		}
+		family, _, _ := strings.Cut(fragment.Get("refresh_token"), ".")
+		session, _ := sessions.FindByFamily(family)
+		if session == nil {
+			t.Fatal("no session was started")
+		}
+		return session.UserID
+	}
+
+	loginError := func(u *url.URL) string {
+		t.Helper()
+		if u.Path != "/login" {
+			t.Fatalf("sent to %s, want /login", u)
+		}
+		return u.Query().Get("error")
+	}
+
+	t.Run("first login creates a user", func(t *testing.T) {
+		userID := signedInAs(signIn(jwt.MapClaims{"sub": "new", "email": "new@example.com", "email_verified": true, "name": "New Person"}))
+		user, _ := users.FindByID(userID)
+		if user == nil || user.Email != "new@example.com" || user.Name != "New Person" || !user.EmailVerified {
+			t.Fatalf("created user %+v", user)
+		}
+
+		// The provider account finds the same user even after its email changes
+		if again := signedInAs(signIn(jwt.MapClaims{"sub": "new", "email": "changed@example.com"})); again != userID {
+			t.Errorf("second login signed in as user %d, want %d", again, userID)
+		}
+	})
+
+	t.Run("links by verified email", func(t *testing.T) {
+		if userID := signedInAs(signIn(jwt.MapClaims{"sub": "linked", "email": "verified@example.com", "email_verified": "true"})); userID != 1 {
+			t.Errorf("signed in as user %d, want 1", userID)
+		}
+		if identity, _ := identities.FindBySubject("corp", "linked"); identity == nil || identity.UserID != 1 {
+			t.Errorf("identity %+v not linked to user 1", identity)
+		}
+	})
+
+	t.Run("refuses to link an unverified account", func(t *testing.T) {
+		if code := loginError(signIn(jwt.MapClaims{"sub": "taker", "email": "unverified@example.com", "email_verified": true})); code != "sso_link_unverified" {
+			t.Errorf("error %q, want sso_link_unverified", code)
+		}
+		if identity, _ := identities.FindBySubject("corp", "taker"); identity != nil {
+			t.Errorf("identity %+v was created", identity)
+		}
+	})
+
+	t.Run("refuses an email the provider has not verified", func(t *testing.T) {
+		if code := loginError(signIn(jwt.MapClaims{"sub": "unchecked", "email": "verified@example.com", "email_verified": false})); code != "sso_email_unverified" {
+			t.Errorf("error %q, want sso_email_unverified", code)
+		}
+	})
+
+	t.Run("rejects a forged ID token", func(t *testing.T) {
+		srv.Edit = func(c jwt.MapClaims) { c["aud"] = "another-client" }
+		defer func() { srv.Edit = nil }()
+		if code := loginError(signIn(jwt.MapClaims{"sub": "new"})); code != "sso_failed" {
+			t.Errorf("error %q, want sso_failed", code)
+		}
+	})
+
+	t.Run("callback without the login cookie", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/corp/callback?code=x&state=y", nil))
+		u, _ := url.Parse(w.Header().Get("Location"))
+		if code := loginError(u); code != "sso_failed" {
+			t.Errorf("error %q, want sso_failed", code)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// resetTokenTTL is how long a password reset link stays valid
+const resetTokenTTL = time.Hour
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ForgotPassword emails a password reset link. The response is the same
+// whether or not the address belongs to an account, so it cannot be used to
+// find out who is registered.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Email == "" {
+		http.Error(w, `{"error":"email is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Sending runs in the background so that response times do not tell
+	// registered addresses apart either
+	go h.sendPasswordReset(req.Email)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(MessageResponse{Message: "if an account exists for this email, a reset link has been sent to it"})
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. The
+// token works once, and every session of the user is signed out.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" || req.Password == "" {
+		http.Error(w, `{"error":"token and password are required"}`, http.StatusBadRequest)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, `{"error":"failed to hash password"}`, http.StatusInternalServerError)
+		return
+	}
+
+	userID, err := h.userRepo.ResetPassword(hashToken(req.Token), string(hash))
+	if err != nil {
+		http.Error(w, `{"error":"failed to reset password"}`, http.StatusInternalServerError)
+		return
+	}
+	if userID == 0 {
+		http.Error(w, `{"error":"invalid or expired token"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sessionRepo.RevokeAllForUser(userID); err != nil {
+		http.Error(w, `{"error":"failed to sign out sessions"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(MessageResponse{Message: "password reset"})
+}
+
+func (h *AuthHandler) sendPasswordReset(email string) {
+	user, err := h.userRepo.FindByEmail(email)
+	if err != nil {
+		logger.Error.Printf("looking up user for password reset: %v", err)
+		return
+	}
+	if user == nil {
+		return
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		logger.Error.Printf("creating password reset token for user %d: %v", user.ID, err)
+		return
+	}
+	if err := h.userRepo.SetResetToken(user.Email, hash, time.Now().Add(resetTokenTTL)); err != nil {
+		logger.Error.Printf("saving password reset token for user %d: %v", user.ID, err)
+		return
+	}
+
+	link := h.clientURL + "/reset-password?token=" + url.QueryEscape(token)
+	err = h.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to choose a new password:\n\n%s\n\nThe link expires in %d minutes. If you did not ask for it, you can ignore this email.\n",
+			user.Name, link, int(resetTokenTTL.Minutes())),
+	})
+	if err != nil {
+		logger.Error.Printf("sending password reset email to user %d: %v", user.ID, err)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestResetPasswordTokenWorksOnce(t *testing.T) {
+	users := &fakeUserRepo{}
+	sessions := &fakeSessionRepo{}
+	users.Create(&model.User{Email: "ada@example.com", Name: "ada"})
+	token, hash, err := newToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.SetResetToken("ada@example.com", hash, time.Now().Add(time.Hour))
+	sessions.Create(&model.Session{UserID: 1, Family: "f1", ExpiresAt: time.Now().Add(time.Hour)})
+
+	h := NewAuthHandler(users, sessions, nil, "secret", discardMailer{}, "http://api", "http://client", nil)
+
+	// Requests racing on one token must not both succeed
+	const attempts = 8
+	codes := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := `{"token":"` + token + `","password":"new password"}`
+			w := httptest.NewRecorder()
+			h.ResetPassword(w, httptest.NewRequest(http.MethodPost, "/auth/password/reset", strings.NewReader(body)))
+			codes <- w.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	succeeded := 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			succeeded++
+		case http.StatusBadRequest:
+		default:
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d resets succeeded, want 1", succeeded)
+	}
+
+	user, _ := users.FindByID(1)
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("new password")) != nil {
+		t.Error("password was not changed")
+	}
+	if active, _ := sessions.FindActiveByUserID(1); len(active) != 0 {
+		t.Errorf("%d sessions still active after reset", len(active))
+	}
+}
@@ -13,10 +13,11 @@ import (
 
 type SchemaHandler struct {
 	schemaRepo repository.SchemaRepository
+	publish    *PublishPolicy
 }
 
-func NewSchemaHandler(schemaRepo repository.SchemaRepository) *SchemaHandler {
-	return &SchemaHandler{schemaRepo: schemaRepo}
+func NewSchemaHandler(schemaRepo repository.SchemaRepository, publish *PublishPolicy) *SchemaHandler {
+	return &SchemaHandler{schemaRepo: schemaRepo, publish: publish}
 }
 
 type CreateSchemaRequest struct {
@@ -29,6 +30,8 @@ type UpdateSchemaRequest struct {
 	Name     *string           `json:"name,omitempty"`
 	Data     *model.SchemaData `json:"data,omitempty"`
 	IsPublic *bool             `json:"is_public,omitempty"`
+	// Message describes the change in the version history
+	Message string `json:"message,omitempty"`
 }
 
 func (h *SchemaHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -49,6 +52,14 @@ func (h *SchemaHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if rejectInvalidSchema(w, req.Data) {
+		return
+	}
+
+	if req.IsPublic && !h.publish.allow(w, userID) {
+		return
+	}
+
 	schema := &model.Schema{
 		UserID:   userID,
 		Name:     req.Name,
@@ -162,13 +173,25 @@ func (h *SchemaHandler) Update(w http.ResponseWriter, r *http.Request) {
 		schema.Name = *req.Name
 	}
 	if req.Data != nil {
+		if rejectInvalidSchema(w, *req.Data) {
+			return
+		}
 		schema.Data = *req.Data
 	}
 	if req.IsPublic != nil {
+		if *req.IsPublic && !schema.IsPublic && !h.publish.allow(w, userID) {
+			return
+		}
 		schema.IsPublic = *req.IsPublic
 	}
 
-	if err := h.schemaRepo.Update(schema); err != nil {
+	// Changes to the name or data are kept as a new version; visibility is not versioned
+	if req.Name != nil || req.Data != nil {
+		err = h.schemaRepo.UpdateWithVersion(schema, &model.SchemaVersion{AuthorID: userID, Message: req.Message})
+	} else {
+		err = h.schemaRepo.Update(schema)
+	}
+	if err != nil {
 		http.Error(w, `{"error":"failed to update schema"}`, http.StatusInternalServerError)
 		return
 	}
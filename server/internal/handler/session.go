@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// accessTokenTTL keeps a leaked access token useful for minutes only
+	accessTokenTTL = 15 * time.Minute
+	// refreshTokenTTL is how long a session lasts without being used
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type SessionResponse struct {
+	model.Session
+	Current bool `json:"current"`
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh
+// token. Each refresh token works once: presenting one that was already
+// rotated means it was copied, so the whole session is revoked.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	family, _, ok := strings.Cut(req.RefreshToken, ".")
+	if !ok {
+		http.Error(w, `{"error":"invalid refresh token"}`, http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.sessionRepo.FindByFamily(family)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		http.Error(w, `{"error":"invalid refresh token"}`, http.StatusUnauthorized)
+		return
+	}
+
+	hash := hashToken(req.RefreshToken)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(session.RefreshHash)) != 1 {
+		h.revokeReusedSession(w, session)
+		return
+	}
+
+	refreshToken, err := sessionRefreshToken(family)
+	if err != nil {
+		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
+		return
+	}
+
+	rotated, err := h.sessionRepo.Rotate(session.ID, hash, hashToken(refreshToken), time.Now().Add(refreshTokenTTL))
+	if err != nil {
+		http.Error(w, `{"error":"failed to refresh session"}`, http.StatusInternalServerError)
+		return
+	}
+	if !rotated {
+		// Another request used the same token first
+		h.revokeReusedSession(w, session)
+		return
+	}
+
+	user, err := h.userRepo.FindByID(session.UserID)
+	if err != nil || user == nil {
+		http.Error(w, `{"error":"user not found"}`, http.StatusUnauthorized)
+		return
+	}
+
+	token, err := h.generateToken(user.ID, session.ID)
+	if err != nil {
+		http.Error(w, `{"error":"failed to generate token"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+		User:         user,
+	})
+}
+
+// Logout ends the session the request was made with
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := middleware.GetSessionID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.sessionRepo.Revoke(sessionID); err != nil {
+		http.Error(w, `{"error":"failed to revoke session"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessions returns the user's active sessions, marking the one the
+// request was made with
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	currentID, _ := middleware.GetSessionID(r.Context())
+
+	sessions, err := h.sessionRepo.FindActiveByUserID(userID)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch sessions"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		response[i] = SessionResponse{Session: s, Current: s.ID == currentID}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeSession signs one of the user's sessions out
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error":"invalid session id"}`, http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.sessionRepo.FindByID(id)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch session"}`, http.StatusInternalServerError)
+		return
+	}
+	if session == nil || session.UserID != userID {
+		http.Error(w, `{"error":"session not found"}`, http.StatusNotFound)
+		return
+	}
+
+	if err := h.sessionRepo.Revoke(session.ID); err != nil {
+		http.Error(w, `{"error":"failed to revoke session"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// startSession signs the user in on a new session and returns its tokens
+func (h *AuthHandler) startSession(r *http.Request, user *model.User) (*AuthResponse, error) {
+	family, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := sessionRefreshToken(family)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &model.Session{
+		UserID:      user.ID,
+		Family:      family,
+		RefreshHash: hashToken(refreshToken),
+		UserAgent:   truncate(r.UserAgent(), 255),
+		IP:          clientIP(r),
+		LastUsedAt:  now,
+		ExpiresAt:   now.Add(refreshTokenTTL),
+	}
+	if err := h.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	token, err := h.generateToken(user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+		User:         user,
+	}, nil
+}
+
+func (h *AuthHandler) revokeReusedSession(w http.ResponseWriter, session *model.Session) {
+	logger.Warn.Printf("refresh token of session %d (user %d) was reused; revoking the session", session.ID, session.UserID)
+	if err := h.sessionRepo.Revoke(session.ID); err != nil {
+		logger.Error.Printf("revoking session %d: %v", session.ID, err)
+	}
+	http.Error(w, `{"error":"refresh token was already used; session revoked"}`, http.StatusUnauthorized)
+}
+
+func (h *AuthHandler) generateToken(userID, sessionID int) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"sid":     sessionID,
+		"exp":     now.Add(accessTokenTTL).Unix(),
+		"iat":     now.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(h.jwtSecret))
+}
+
+// sessionRefreshToken returns a new refresh token for the session family
+func sessionRefreshToken(family string) (string, error) {
+	secret, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return family + "." + secret, nil
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// truncate shortens s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
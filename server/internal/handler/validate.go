@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/validator"
+)
+
+type ValidateHandler struct{}
+
+func NewValidateHandler() *ValidateHandler {
+	return &ValidateHandler{}
+}
+
+type ValidateRequest struct {
+	Data model.SchemaData `json:"data"`
+}
+
+type ValidateResponse struct {
+	Valid       bool                   `json:"valid"`
+	Diagnostics []validator.Diagnostic `json:"diagnostics"`
+}
+
+type ValidationErrorResponse struct {
+	Error       string                 `json:"error"`
+	Diagnostics []validator.Diagnostic `json:"diagnostics"`
+}
+
+// Validate checks schema data without saving it. Warnings alone leave the
+// schema valid.
+func (h *ValidateHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	var req ValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	diagnostics := validator.Validate(req.Data)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ValidateResponse{
+		Valid:       !validator.HasErrors(diagnostics),
+		Diagnostics: diagnostics,
+	})
+}
+
+// rejectInvalidSchema writes a 422 with the diagnostics and returns true when
+// the schema data has errors
+func rejectInvalidSchema(w http.ResponseWriter, data model.SchemaData) bool {
+	diagnostics := validator.Validate(data)
+	if !validator.HasErrors(diagnostics) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(ValidationErrorResponse{
+		Error:       "schema is invalid",
+		Diagnostics: diagnostics,
+	})
+	return true
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/mailer"
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/repository"
+)
+
+// verifyTokenTTL is how long an email verification link stays valid
+const verifyTokenTTL = 24 * time.Hour
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// RequestVerification sends the signed in user a new verification link,
+// replacing any earlier one
+func (h *AuthHandler) RequestVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		http.Error(w, `{"error":"user not found"}`, http.StatusNotFound)
+		return
+	}
+	if user.EmailVerified {
+		http.Error(w, `{"error":"email already verified"}`, http.StatusConflict)
+		return
+	}
+
+	if err := h.sendVerification(user); err != nil {
+		logger.Error.Printf("sending verification email to user %d: %v", user.ID, err)
+		http.Error(w, `{"error":"failed to send verification email"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(MessageResponse{Message: "verification email sent"})
+}
+
+// VerifyEmail marks the email of the user the token was sent to as verified.
+// Tokens work once and expire after verifyTokenTTL.
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, `{"error":"token is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userRepo.VerifyEmail(hashToken(token)); err != nil {
+		http.Error(w, `{"error":"invalid or expired token"}`, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(MessageResponse{Message: "email verified"})
+}
+
+func (h *AuthHandler) sendVerification(user *model.User) error {
+	token, hash, err := newToken()
+	if err != nil {
+		return err
+	}
+	if err := h.userRepo.SetVerifyToken(user.Email, hash, time.Now().Add(verifyTokenTTL)); err != nil {
+		return err
+	}
+
+	link := h.apiURL + "/api/auth/verify?token=" + url.QueryEscape(token)
+	return h.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to verify your email address:\n\n%s\n\nThe link expires in %d hours.\n",
+			user.Name, link, int(verifyTokenTTL.Hours())),
+	})
+}
+
+// PublishPolicy decides who may make schemas public
+type PublishPolicy struct {
+	userRepo        repository.UserRepository
+	requireVerified bool
+}
+
+func NewPublishPolicy(userRepo repository.UserRepository, requireVerified bool) *PublishPolicy {
+	return &PublishPolicy{userRepo: userRepo, requireVerified: requireVerified}
+}
+
+// allow writes an error response and returns false when the user may not
+// publish schemas
+func (p *PublishPolicy) allow(w http.ResponseWriter, userID int) bool {
+	if !p.requireVerified {
+		return true
+	}
+
+	user, err := p.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		http.Error(w, `{"error":"user not found"}`, http.StatusNotFound)
+		return false
+	}
+	if !user.EmailVerified {
+		http.Error(w, `{"error":"verify your email address before publishing schemas"}`, http.StatusForbidden)
+		return false
+	}
+	return true
+}
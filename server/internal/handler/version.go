@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/middleware"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/Dragodui/db-schemas-generator/internal/repository"
+	"github.com/go-chi/chi/v5"
+)
+
+type VersionHandler struct {
+	schemaRepo  repository.SchemaRepository
+	versionRepo repository.SchemaVersionRepository
+}
+
+func NewVersionHandler(schemaRepo repository.SchemaRepository, versionRepo repository.SchemaVersionRepository) *VersionHandler {
+	return &VersionHandler{schemaRepo: schemaRepo, versionRepo: versionRepo}
+}
+
+// VersionSummary describes a version in listings, which leave out the data
+type VersionSummary struct {
+	Version   int       `json:"version"`
+	AuthorID  int       `json:"author_id"`
+	Message   string    `json:"message"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RestoreVersionRequest struct {
+	Message string `json:"message,omitempty"`
+}
+
+// List returns the versions of a schema, newest first
+func (h *VersionHandler) List(w http.ResponseWriter, r *http.Request) {
+	schema, ok := loadSchema(w, r, h.schemaRepo)
+	if !ok {
+		return
+	}
+
+	versions, err := h.versionRepo.FindBySchemaID(schema.ID)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch versions"}`, http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]VersionSummary, len(versions))
+	for i, v := range versions {
+		summaries[i] = VersionSummary{
+			Version:   v.Version,
+			AuthorID:  v.AuthorID,
+			Message:   v.Message,
+			Name:      v.Name,
+			CreatedAt: v.CreatedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// Get returns a single version with its data
+func (h *VersionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	schema, ok := loadSchema(w, r, h.schemaRepo)
+	if !ok {
+		return
+	}
+
+	version, ok := h.loadVersion(w, schema.ID, chi.URLParam(r, "version"))
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version)
+}
+
+// Restore makes an earlier version the schema's current state. The restore is
+// itself recorded as a new version, so no history is lost. Restored data is
+// not validated again, so that work saved under older rules can always be
+// recovered.
+func (h *VersionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	schema, ok := loadSchema(w, r, h.schemaRepo)
+	if !ok {
+		return
+	}
+
+	version, ok := h.loadVersion(w, schema.ID, chi.URLParam(r, "version"))
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req RestoreVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, `{"error":"invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+	if req.Message == "" {
+		req.Message = fmt.Sprintf("Restored version %d", version.Version)
+	}
+
+	schema.Name = version.Name
+	schema.Data = version.Data
+	if err := h.schemaRepo.UpdateWithVersion(schema, &model.SchemaVersion{AuthorID: userID, Message: req.Message}); err != nil {
+		http.Error(w, `{"error":"failed to restore version"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schema)
+}
+
+// loadVersion fetches a version of the schema by its number as given in the
+// request. On failure it writes the error response and returns false.
+func (h *VersionHandler) loadVersion(w http.ResponseWriter, schemaID int, raw string) (*model.SchemaVersion, bool) {
+	number, err := strconv.Atoi(raw)
+	if err != nil {
+		http.Error(w, `{"error":"invalid version"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	version, err := h.versionRepo.FindByVersion(schemaID, number)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch version"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	if version == nil {
+		http.Error(w, `{"error":"version not found"}`, http.StatusNotFound)
+		return nil, false
+	}
+	return version, true
+}
+
+// loadSchema fetches the schema named by the {id} URL parameter and checks
+// that the caller owns it. The version history stays private even for public
+// schemas, since older versions may hold drafts from before publishing. On
+// failure it writes the error response and returns false.
+func loadSchema(w http.ResponseWriter, r *http.Request, repo repository.SchemaRepository) (*model.Schema, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error":"invalid schema id"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	schema, err := repo.FindByID(id)
+	if err != nil {
+		http.Error(w, `{"error":"failed to fetch schema"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	if schema == nil {
+		http.Error(w, `{"error":"schema not found"}`, http.StatusNotFound)
+		return nil, false
+	}
+
+	userID, hasUser := middleware.GetUserID(r.Context())
+	if !hasUser || schema.UserID != userID {
+		http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+		return nil, false
+	}
+	return schema, true
+}
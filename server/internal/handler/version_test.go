@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVersionHistoryIsPrivate(t *testing.T) {
+	repo := newFakeSchemaRepo()
+	schema := &model.Schema{UserID: 1, Name: "shop", Data: model.SchemaData{Tables: []model.Table{{Name: "draft_secrets"}}}}
+	repo.Create(schema)
+	schema.Data = model.SchemaData{Tables: []model.Table{{Name: "products"}}}
+	schema.IsPublic = true
+	repo.UpdateWithVersion(schema, &model.SchemaVersion{AuthorID: 1})
+
+	h := NewVersionHandler(repo, repo)
+	r := chi.NewRouter()
+	r.Get("/schemas/{id}/versions", h.List)
+	r.Get("/schemas/{id}/versions/{version}", h.Get)
+	r.Get("/schemas/{id}/diff", h.Diff)
+	r.Get("/schemas/{id}/migration", h.Migration)
+
+	paths := []string{
+		"/schemas/1/versions",
+		"/schemas/1/versions/1",
+		"/schemas/1/diff?from=1&to=2",
+		"/schemas/1/migration?from=1&to=2",
+	}
+	for _, path := range paths {
+		for _, tt := range []struct {
+			name   string
+			userID int
+			want   int
+		}{
+			{"anonymous", 0, http.StatusForbidden},
+			{"other user", 2, http.StatusForbidden},
+			{"owner", 1, http.StatusOK},
+		} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tt.userID != 0 {
+				req = asUser(req, tt.userID)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("%s as %s: status %d, want %d: %s", path, tt.name, w.Code, tt.want, w.Body)
+			}
+		}
+	}
+}
+
+func TestMigrationErrorIsJSON(t *testing.T) {
+	repo := newFakeSchemaRepo()
+	repo.Create(&model.Schema{UserID: 1, Name: "shop"})
+
+	h := NewVersionHandler(repo, repo)
+	r := chi.NewRouter()
+	r.Get("/schemas/{id}/migration", h.Migration)
+
+	format := url.QueryEscape(`x"}, "admin": true, "y":"`)
+	req := asUser(httptest.NewRequest(http.MethodGet, "/schemas/1/migration?from=1&to=1&format="+format, nil), 1)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body is not JSON: %v: %s", err, w.Body)
+	}
+	if len(body) != 1 || body["error"] == nil {
+		t.Errorf("error body = %v, want only an error", body)
+	}
+}
@@ -0,0 +1,904 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+type dbmlTokenKind int
+
+const (
+	dbmlEOF dbmlTokenKind = iota
+	dbmlNewline
+	dbmlIdent  // bare word or "double quoted" name
+	dbmlString // 'single quoted' or '''multi-line''' string
+	dbmlExpr   // `backtick` expression
+	dbmlNumber
+	dbmlPunct
+)
+
+type dbmlToken struct {
+	kind dbmlTokenKind
+	text string
+	line int
+}
+
+func (t dbmlToken) is(kind dbmlTokenKind, text string) bool {
+	if t.kind != kind {
+		return false
+	}
+	if kind == dbmlIdent {
+		return strings.EqualFold(t.text, text)
+	}
+	return t.text == text
+}
+
+func lexDBML(input string) ([]dbmlToken, error) {
+	var tokens []dbmlToken
+	runes := []rune(input)
+	line := 1
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case r == '\n':
+			tokens = append(tokens, dbmlToken{kind: dbmlNewline, text: "\n", line: line})
+			line++
+			i++
+
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+
+		case r == '\'' && i+2 < len(runes) && runes[i+1] == '\'' && runes[i+2] == '\'':
+			start := line
+			i += 3
+			var sb strings.Builder
+			for i < len(runes) && !(runes[i] == '\'' && i+2 < len(runes) && runes[i+1] == '\'' && runes[i+2] == '\'') {
+				if runes[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated string", start)
+			}
+			i += 3
+			tokens = append(tokens, dbmlToken{kind: dbmlString, text: sb.String(), line: start})
+
+		case r == '\'' || r == '"' || r == '`':
+			start := line
+			quote := r
+			i++
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != quote {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+			}
+			i++
+
+			kind := dbmlIdent
+			if quote == '\'' {
+				kind = dbmlString
+			} else if quote == '`' {
+				kind = dbmlExpr
+			}
+			tokens = append(tokens, dbmlToken{kind: kind, text: sb.String(), line: start})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, dbmlToken{kind: dbmlNumber, text: string(runes[start:i]), line: line})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, dbmlToken{kind: dbmlIdent, text: string(runes[start:i]), line: line})
+
+		case r == '<' && i+1 < len(runes) && runes[i+1] == '>':
+			tokens = append(tokens, dbmlToken{kind: dbmlPunct, text: "<>", line: line})
+			i += 2
+
+		case strings.ContainsRune("{}[](),:.<>-#~", r):
+			tokens = append(tokens, dbmlToken{kind: dbmlPunct, text: string(r), line: line})
+			i++
+
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, r)
+		}
+	}
+
+	tokens = append(tokens, dbmlToken{kind: dbmlEOF, line: line})
+	return tokens, nil
+}
+
+// dbmlEndpoint is one side of a Ref, e.g. posts.user_id
+type dbmlEndpoint struct {
+	table   string
+	columns []string
+}
+
+type dbmlRef struct {
+	name        string
+	left, right dbmlEndpoint
+	op          string
+	settings    map[string]string
+	line        int
+}
+
+type dbmlParser struct {
+	tokens  []dbmlToken
+	pos     int
+	result  *Result
+	aliases map[string]string
+	enums   map[string][]string
+	refs    []dbmlRef
+}
+
+func importDBML(input string) (*Result, error) {
+	tokens, err := lexDBML(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dbmlParser{
+		tokens:  tokens,
+		result:  &Result{Data: model.SchemaData{Tables: []model.Table{}}},
+		aliases: make(map[string]string),
+		enums:   make(map[string][]string),
+	}
+
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	p.resolve()
+
+	return p.result, nil
+}
+
+func (p *dbmlParser) peek() dbmlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dbmlParser) next() dbmlToken {
+	t := p.tokens[p.pos]
+	if t.kind != dbmlEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *dbmlParser) skipNewlines() {
+	for p.peek().kind == dbmlNewline {
+		p.pos++
+	}
+}
+
+func (p *dbmlParser) expect(kind dbmlTokenKind, text string) error {
+	t := p.next()
+	if !t.is(kind, text) {
+		return fmt.Errorf("line %d: expected %q, got %q", t.line, text, t.text)
+	}
+	return nil
+}
+
+func (p *dbmlParser) parse() error {
+	for {
+		p.skipNewlines()
+		t := p.peek()
+
+		switch {
+		case t.kind == dbmlEOF:
+			return nil
+		case t.is(dbmlIdent, "Table"):
+			p.next()
+			if err := p.parseTable(); err != nil {
+				return err
+			}
+		case t.is(dbmlIdent, "Ref"):
+			p.next()
+			if err := p.parseRef(); err != nil {
+				return err
+			}
+		case t.is(dbmlIdent, "Enum"):
+			p.next()
+			if err := p.parseEnum(); err != nil {
+				return err
+			}
+		case t.kind == dbmlIdent:
+			// Project, TableGroup, Note, Records and other elements carry no schema structure
+			p.next()
+			p.result.warnf("line %d: %s element ignored", t.line, t.text)
+			if err := p.skipElement(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("line %d: unexpected %q", t.line, t.text)
+		}
+	}
+}
+
+// skipElement consumes the rest of an element, either up to the end of its
+// line or through its balanced { } block
+func (p *dbmlParser) skipElement() error {
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == dbmlEOF || t.kind == dbmlNewline:
+			return nil
+		case t.is(dbmlPunct, "{"):
+			return p.skipBlock()
+		default:
+			p.next()
+		}
+	}
+}
+
+func (p *dbmlParser) skipBlock() error {
+	start := p.peek().line
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		t := p.next()
+		switch {
+		case t.kind == dbmlEOF:
+			return fmt.Errorf("line %d: unterminated block", start)
+		case t.is(dbmlPunct, "{"):
+			depth++
+		case t.is(dbmlPunct, "}"):
+			depth--
+		}
+	}
+	return nil
+}
+
+// parseName reads a possibly schema-qualified name; the default public
+// schema is dropped
+func (p *dbmlParser) parseName() (string, error) {
+	t := p.next()
+	if t.kind != dbmlIdent {
+		return "", fmt.Errorf("line %d: expected name, got %q", t.line, t.text)
+	}
+	parts := []string{t.text}
+	for p.peek().is(dbmlPunct, ".") && p.tokens[p.pos+1].kind == dbmlIdent {
+		p.next()
+		parts = append(parts, p.next().text)
+	}
+	if len(parts) > 1 && parts[0] == "public" {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// parseSettings reads a [ ... ] list into lowercased keys and raw values,
+// e.g. [pk, default: 'x'] -> {"pk": "", "default": "x"}
+func (p *dbmlParser) parseSettings() (map[string]string, error) {
+	settings := make(map[string]string)
+	start := p.peek().line
+	if err := p.expect(dbmlPunct, "["); err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipNewlines()
+		var keyParts []string
+		value := ""
+		hasValue := false
+
+		for {
+			t := p.peek()
+			if t.kind == dbmlEOF {
+				return nil, fmt.Errorf("line %d: unterminated settings", start)
+			}
+			if t.is(dbmlPunct, ",") || t.is(dbmlPunct, "]") {
+				break
+			}
+			p.next()
+			if !hasValue && t.is(dbmlPunct, ":") {
+				hasValue = true
+				continue
+			}
+			if hasValue {
+				if t.kind == dbmlPunct || value == "" {
+					value += t.text
+				} else {
+					value += " " + t.text
+				}
+			} else if t.kind != dbmlNewline {
+				keyParts = append(keyParts, strings.ToLower(t.text))
+			}
+		}
+
+		if len(keyParts) > 0 {
+			settings[strings.Join(keyParts, " ")] = strings.TrimSpace(value)
+		}
+
+		if p.next().is(dbmlPunct, "]") {
+			return settings, nil
+		}
+	}
+}
+
+// parseColumnSettings applies a column's [ ... ] settings; it walks the tokens
+// itself so that defaults can tell strings from expressions and inline refs
+// can be recorded
+func (p *dbmlParser) parseColumnSettings(col *model.Column, table string) error {
+	start := p.peek().line
+	if err := p.expect(dbmlPunct, "["); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		t := p.next()
+		switch {
+		case t.kind == dbmlEOF:
+			return fmt.Errorf("line %d: unterminated settings", start)
+		case t.is(dbmlPunct, "]"):
+			return nil
+		case t.is(dbmlPunct, ","):
+			continue
+		case t.is(dbmlIdent, "pk"):
+			col.PrimaryKey = true
+		case t.is(dbmlIdent, "primary") && p.peek().is(dbmlIdent, "key"):
+			p.next()
+			col.PrimaryKey = true
+		case t.is(dbmlIdent, "increment"):
+			col.AutoIncrement = true
+		case t.is(dbmlIdent, "not") && p.peek().is(dbmlIdent, "null"):
+			p.next()
+			col.NotNull = true
+		case t.is(dbmlIdent, "null"):
+			col.NotNull = false
+		case t.is(dbmlIdent, "unique"):
+			col.Unique = true
+		case t.is(dbmlIdent, "default") && p.peek().is(dbmlPunct, ":"):
+			p.next()
+			def, err := p.parseDefault()
+			if err != nil {
+				return err
+			}
+			col.Default = def
+		case t.is(dbmlIdent, "ref") && p.peek().is(dbmlPunct, ":"):
+			p.next()
+			op := p.next()
+			if op.kind != dbmlPunct || !strings.Contains("<>-", op.text) {
+				return fmt.Errorf("line %d: invalid inline ref", op.line)
+			}
+			right, err := p.parseEndpoint()
+			if err != nil {
+				return err
+			}
+			p.refs = append(p.refs, dbmlRef{
+				left:     dbmlEndpoint{table: table, columns: []string{col.Name}},
+				right:    right,
+				op:       op.text,
+				settings: map[string]string{},
+				line:     op.line,
+			})
+		case t.is(dbmlIdent, "note") && p.peek().is(dbmlPunct, ":") && p.tokens[p.pos+1].kind == dbmlString:
+			p.next()
+			col.Comment = p.next().text
+		default:
+			// check: `...` and anything unknown are skipped
+			for !p.peek().is(dbmlPunct, ",") && !p.peek().is(dbmlPunct, "]") && p.peek().kind != dbmlEOF {
+				p.next()
+			}
+		}
+	}
+}
+
+func (p *dbmlParser) parseDefault() (*string, error) {
+	t := p.next()
+	var value string
+	switch {
+	case t.kind == dbmlString || t.kind == dbmlExpr || t.kind == dbmlNumber:
+		value = t.text
+	case t.is(dbmlPunct, "-") && p.peek().kind == dbmlNumber:
+		value = "-" + p.next().text
+	case t.is(dbmlIdent, "null"):
+		value = "NULL"
+	case t.kind == dbmlIdent:
+		value = t.text
+	default:
+		return nil, fmt.Errorf("line %d: invalid default value %q", t.line, t.text)
+	}
+	return &value, nil
+}
+
+// quotedTypeArgsRe splits the size arguments off a quoted type such as
+// "decimal(10,2)"
+var quotedTypeArgsRe = regexp.MustCompile(`^(.*?)\s*\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\)$`)
+
+func (p *dbmlParser) parseType() (string, []int, error) {
+	t := p.next()
+	if t.kind != dbmlIdent {
+		return "", nil, fmt.Errorf("line %d: expected column type, got %q", t.line, t.text)
+	}
+	typ := t.text
+	for p.peek().is(dbmlPunct, ".") {
+		p.next()
+		typ += "." + p.next().text
+	}
+
+	// Size arguments such as varchar(255) or decimal(10, 2)
+	var args []int
+	if m := quotedTypeArgsRe.FindStringSubmatch(typ); m != nil {
+		typ = m[1]
+		for _, arg := range m[2:] {
+			if arg != "" {
+				n, _ := strconv.Atoi(arg)
+				args = append(args, n)
+			}
+		}
+	} else if p.peek().is(dbmlPunct, "(") {
+		p.next()
+		for !p.peek().is(dbmlPunct, ")") {
+			arg := p.next()
+			switch {
+			case arg.kind == dbmlEOF:
+				return "", nil, fmt.Errorf("line %d: unterminated type arguments", t.line)
+			case arg.kind == dbmlNumber:
+				n, _ := strconv.Atoi(arg.text)
+				args = append(args, n)
+			}
+		}
+		p.next()
+	}
+
+	// An empty [] marks an array type rather than a settings list
+	if p.peek().is(dbmlPunct, "[") && p.tokens[p.pos+1].is(dbmlPunct, "]") {
+		p.next()
+		p.next()
+		typ += "[]"
+	}
+
+	return typ, args, nil
+}
+
+func (p *dbmlParser) parseTable() error {
+	line := p.peek().line
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	if p.peek().is(dbmlIdent, "as") {
+		p.next()
+		alias := p.next()
+		p.aliases[alias.text] = name
+	}
+
+	if p.peek().is(dbmlPunct, "[") {
+		if _, err := p.parseSettings(); err != nil {
+			return err
+		}
+	}
+
+	if findTable(&p.result.Data, name) != nil {
+		return fmt.Errorf("line %d: duplicate table %s", line, name)
+	}
+
+	table := model.Table{Name: name, Columns: []model.Column{}}
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		t := p.peek()
+
+		switch {
+		case t.kind == dbmlEOF:
+			return fmt.Errorf("line %d: unterminated table %s", line, name)
+
+		case t.is(dbmlPunct, "}"):
+			p.next()
+			p.result.Data.Tables = append(p.result.Data.Tables, table)
+			return nil
+
+		case t.is(dbmlIdent, "indexes") && p.tokens[p.pos+1].is(dbmlPunct, "{"):
+			p.next()
+			if err := p.parseIndexes(&table); err != nil {
+				return err
+			}
+
+		case t.is(dbmlIdent, "checks") && p.tokens[p.pos+1].is(dbmlPunct, "{"):
+			p.next()
+			if err := p.parseChecks(&table); err != nil {
+				return err
+			}
+
+		case t.is(dbmlIdent, "Note") && (p.tokens[p.pos+1].is(dbmlPunct, ":") || p.tokens[p.pos+1].is(dbmlPunct, "{")):
+			p.next()
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			table.Comment = note
+
+		case t.kind == dbmlIdent:
+			p.next()
+			col := model.Column{Name: t.text}
+			typ, args, err := p.parseType()
+			if err != nil {
+				return err
+			}
+			// Kept as declared until resolve, as it may name an enum defined further down
+			col.Type = typ
+			applyTypeArgs(&col, args)
+
+			if p.peek().is(dbmlPunct, "[") {
+				if err := p.parseColumnSettings(&col, name); err != nil {
+					return err
+				}
+			}
+			if col.PrimaryKey {
+				col.NotNull = true
+			}
+			table.Columns = append(table.Columns, col)
+
+		case t.is(dbmlPunct, "~"):
+			p.result.warnf("line %d: table partial in table %s ignored", t.line, name)
+			for p.peek().kind != dbmlNewline && p.peek().kind != dbmlEOF {
+				p.next()
+			}
+
+		default:
+			return fmt.Errorf("line %d: unexpected %q in table %s", t.line, t.text, name)
+		}
+	}
+}
+
+func (p *dbmlParser) parseIndexes(table *model.Table) error {
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		t := p.next()
+
+		var columns []string
+		expression := false
+		switch {
+		case t.kind == dbmlEOF:
+			return fmt.Errorf("line %d: unterminated indexes block", t.line)
+		case t.is(dbmlPunct, "}"):
+			return nil
+		case t.is(dbmlPunct, "("):
+			for {
+				c := p.next()
+				if c.is(dbmlPunct, ")") {
+					break
+				}
+				if c.kind == dbmlEOF {
+					return fmt.Errorf("line %d: unterminated index columns", t.line)
+				}
+				if c.kind == dbmlIdent || c.kind == dbmlExpr {
+					columns = append(columns, c.text)
+					expression = expression || c.kind == dbmlExpr
+				}
+			}
+		case t.kind == dbmlIdent || t.kind == dbmlExpr:
+			columns = []string{t.text}
+			expression = t.kind == dbmlExpr
+		default:
+			return fmt.Errorf("line %d: unexpected %q in indexes", t.line, t.text)
+		}
+
+		settings := map[string]string{}
+		if p.peek().is(dbmlPunct, "[") {
+			var err error
+			if settings, err = p.parseSettings(); err != nil {
+				return err
+			}
+		}
+
+		_, pk := settings["pk"]
+		_, unique := settings["unique"]
+		switch {
+		case pk:
+			for _, c := range columns {
+				if col := findColumn(table, c); col != nil {
+					col.PrimaryKey = true
+					col.NotNull = true
+				}
+			}
+		case unique && len(columns) == 1 && settings["name"] == "" && findColumn(table, columns[0]) != nil:
+			findColumn(table, columns[0]).Unique = true
+		case expression:
+			p.result.warnf("line %d: expression index (%s) on table %s ignored", t.line, strings.Join(columns, ", "), table.Name)
+		case unique && settings["type"] == "":
+			table.Uniques = append(table.Uniques, model.Unique{Name: settings["name"], Columns: columns})
+		default:
+			idx := model.Index{Name: settings["name"], Unique: unique, Method: settings["type"]}
+			for _, c := range columns {
+				idx.Columns = append(idx.Columns, model.IndexColumn{Name: c})
+			}
+			table.Indexes = append(table.Indexes, idx)
+		}
+	}
+}
+
+// parseNote reads the value of Note: '...' or Note { '...' }
+func (p *dbmlParser) parseNote() (string, error) {
+	if p.peek().is(dbmlPunct, ":") {
+		p.next()
+		t := p.next()
+		if t.kind != dbmlString {
+			return "", fmt.Errorf("line %d: expected note string, got %q", t.line, t.text)
+		}
+		return t.text, nil
+	}
+
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return "", err
+	}
+	p.skipNewlines()
+	t := p.next()
+	if t.kind != dbmlString {
+		return "", fmt.Errorf("line %d: expected note string, got %q", t.line, t.text)
+	}
+	p.skipNewlines()
+	if err := p.expect(dbmlPunct, "}"); err != nil {
+		return "", err
+	}
+	return t.text, nil
+}
+
+// parseChecks reads a checks block, whose entries are `expression` [name: 'x']
+func (p *dbmlParser) parseChecks(table *model.Table) error {
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		t := p.next()
+
+		switch {
+		case t.kind == dbmlEOF:
+			return fmt.Errorf("line %d: unterminated checks block", t.line)
+		case t.is(dbmlPunct, "}"):
+			return nil
+		case t.kind != dbmlExpr:
+			return fmt.Errorf("line %d: unexpected %q in checks", t.line, t.text)
+		}
+
+		check := model.Check{Expression: strings.TrimSpace(t.text)}
+		if p.peek().is(dbmlPunct, "[") {
+			settings, err := p.parseSettings()
+			if err != nil {
+				return err
+			}
+			check.Name = settings["name"]
+		}
+		table.Checks = append(table.Checks, check)
+	}
+}
+
+// parseEndpoint reads table.column or table.(col1, col2)
+func (p *dbmlParser) parseEndpoint() (dbmlEndpoint, error) {
+	var parts []string
+	line := p.peek().line
+
+	for {
+		t := p.next()
+		if t.is(dbmlPunct, "(") {
+			var cols []string
+			for {
+				c := p.next()
+				if c.is(dbmlPunct, ")") {
+					break
+				}
+				if c.kind == dbmlEOF {
+					return dbmlEndpoint{}, fmt.Errorf("line %d: unterminated column list", line)
+				}
+				if c.kind == dbmlIdent {
+					cols = append(cols, c.text)
+				}
+			}
+			return dbmlEndpoint{table: p.tableName(parts), columns: cols}, nil
+		}
+		if t.kind != dbmlIdent {
+			return dbmlEndpoint{}, fmt.Errorf("line %d: invalid ref endpoint", line)
+		}
+		parts = append(parts, t.text)
+		if !p.peek().is(dbmlPunct, ".") {
+			break
+		}
+		p.next()
+	}
+
+	if len(parts) < 2 {
+		return dbmlEndpoint{}, fmt.Errorf("line %d: ref endpoint needs table.column", line)
+	}
+	return dbmlEndpoint{table: p.tableName(parts[:len(parts)-1]), columns: parts[len(parts)-1:]}, nil
+}
+
+func (p *dbmlParser) tableName(parts []string) string {
+	if len(parts) > 1 && parts[0] == "public" {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, ".")
+}
+
+func (p *dbmlParser) parseRefLine(name string) error {
+	line := p.peek().line
+	left, err := p.parseEndpoint()
+	if err != nil {
+		return err
+	}
+	op := p.next()
+	if op.kind != dbmlPunct || !(op.text == ">" || op.text == "<" || op.text == "-" || op.text == "<>") {
+		return fmt.Errorf("line %d: invalid ref operator %q", op.line, op.text)
+	}
+	right, err := p.parseEndpoint()
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]string{}
+	if p.peek().is(dbmlPunct, "[") {
+		if settings, err = p.parseSettings(); err != nil {
+			return err
+		}
+	}
+
+	p.refs = append(p.refs, dbmlRef{name: name, left: left, right: right, op: op.text, settings: settings, line: line})
+	return nil
+}
+
+func (p *dbmlParser) parseRef() error {
+	// Optional ref name, kept as the constraint name
+	name := ""
+	if p.peek().kind == dbmlIdent {
+		name = p.next().text
+	}
+
+	if p.peek().is(dbmlPunct, ":") {
+		p.next()
+		return p.parseRefLine(name)
+	}
+
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.peek().is(dbmlPunct, "}") {
+			p.next()
+			return nil
+		}
+		if err := p.parseRefLine(name); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dbmlParser) parseEnum() error {
+	line := p.peek().line
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(dbmlPunct, "{"); err != nil {
+		return err
+	}
+
+	var values []string
+	for {
+		p.skipNewlines()
+		t := p.next()
+		switch {
+		case t.kind == dbmlEOF:
+			return fmt.Errorf("line %d: unterminated enum %s", line, name)
+		case t.is(dbmlPunct, "}"):
+			p.enums[name] = values
+			return nil
+		case t.kind == dbmlIdent || t.kind == dbmlNumber:
+			values = append(values, t.text)
+			if p.peek().is(dbmlPunct, "[") {
+				if _, err := p.parseSettings(); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("line %d: unexpected %q in enum %s", t.line, t.text, name)
+		}
+	}
+}
+
+// resolve links enum types and refs once every element has been read, since
+// DBML allows them to be declared in any order
+func (p *dbmlParser) resolve() {
+	data := &p.result.Data
+
+	for i := range data.Tables {
+		for j := range data.Tables[i].Columns {
+			col := &data.Tables[i].Columns[j]
+			name := strings.TrimPrefix(col.Type, "public.")
+			if values, ok := p.enums[name]; ok {
+				col.Type = "ENUM"
+				col.EnumValues = values
+			} else {
+				col.Type = strings.ToUpper(col.Type)
+			}
+		}
+	}
+
+	for _, ref := range p.refs {
+		from, to := ref.left, ref.right
+		switch ref.op {
+		case "<":
+			from, to = to, from
+		case "<>":
+			p.result.warnf("line %d: many-to-many ref between %s and %s ignored", ref.line, from.table, to.table)
+			continue
+		}
+
+		if alias, ok := p.aliases[from.table]; ok {
+			from.table = alias
+		}
+		if alias, ok := p.aliases[to.table]; ok {
+			to.table = alias
+		}
+
+		if len(from.columns) != len(to.columns) {
+			p.result.warnf("line %d: ref from %s to %s with mismatched columns ignored", ref.line, from.table, to.table)
+			continue
+		}
+
+		table := findTable(data, from.table)
+		if table == nil {
+			p.result.warnf("line %d: ref from unknown table %s ignored", ref.line, from.table)
+			continue
+		}
+
+		fk := newForeignKey(from.columns, to.table, to.columns)
+		fk.Name = ref.name
+		fk.OnDelete = strings.ToUpper(ref.settings["delete"])
+		fk.OnUpdate = strings.ToUpper(ref.settings["update"])
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+	}
+}
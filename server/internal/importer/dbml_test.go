@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestImportDBML(t *testing.T) {
+	runImportTests(t, FormatDBML, []importTest{
+		{
+			name: "tables, enums and refs",
+			input: `// dbdiagram export
+Project shop { database_type: 'PostgreSQL' }
+
+Enum order_status {
+  pending
+  "in progress" [note: 'working']
+}
+
+Table users as U {
+  id integer [pk, increment]
+  email varchar(255) [not null, unique]
+  bio text [default: 'n/a', note: 'about']
+  Note: 'people'
+}
+
+Table orders {
+  id integer [pk]
+  user_id integer [ref: > U.id]
+  status order_status [default: 'pending']
+  total "decimal(10,2)" [default: 0]
+  created_at timestamp [default: ` + "`now()`" + `]
+}
+
+Ref: orders.id < tags.order_id [delete: cascade]
+
+Table tags {
+  order_id integer
+  name varchar
+}
+`,
+			want: []model.Table{
+				{
+					Name: "users",
+					Columns: []model.Column{
+						{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true, AutoIncrement: true},
+						{Name: "email", Type: "VARCHAR", Length: 255, NotNull: true, Unique: true},
+						{Name: "bio", Type: "TEXT", Default: ptr("n/a"), Comment: "about"},
+					},
+					Comment: "people",
+				},
+				{
+					Name: "orders",
+					Columns: []model.Column{
+						{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+						{Name: "user_id", Type: "INTEGER"},
+						{Name: "status", Type: "ENUM", Default: ptr("pending"), EnumValues: []string{"pending", "in progress"}},
+						{Name: "total", Type: "DECIMAL", Precision: 10, Scale: 2, Default: ptr("0")},
+						{Name: "created_at", Type: "TIMESTAMP", Default: ptr("now()")},
+					},
+					ForeignKeys: []model.ForeignKey{{Column: "user_id", References: model.Reference{Table: "users", Column: "id"}}},
+				},
+				{
+					Name: "tags",
+					Columns: []model.Column{
+						{Name: "order_id", Type: "INTEGER"},
+						{Name: "name", Type: "VARCHAR"},
+					},
+					ForeignKeys: []model.ForeignKey{{
+						Column:     "order_id",
+						References: model.Reference{Table: "orders", Column: "id"},
+						OnDelete:   "CASCADE",
+					}},
+				},
+			},
+			warnings: []string{"line 2: Project element ignored"},
+		},
+		{
+			name: "indexes",
+			input: `Table orders {
+  id integer [pk]
+  user_id integer
+  status varchar
+  created_at timestamp
+
+  indexes {
+    (user_id, created_at) [name: 'orders_user_created']
+    status [unique]
+    ` + "`lower(status)`" + `
+  }
+}
+`,
+			want: []model.Table{{
+				Name: "orders",
+				Columns: []model.Column{
+					{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+					{Name: "user_id", Type: "INTEGER"},
+					{Name: "status", Type: "VARCHAR", Unique: true},
+					{Name: "created_at", Type: "TIMESTAMP"},
+				},
+				Indexes: []model.Index{{
+					Name:    "orders_user_created",
+					Columns: []model.IndexColumn{{Name: "user_id"}, {Name: "created_at"}},
+				}},
+			}},
+			warnings: []string{"line 10: expression index (lower(status)) on table orders ignored"},
+		},
+		{
+			name: "unsupported elements are warnings",
+			input: `Table a {
+  id integer [pk]
+  b_id integer
+}
+
+Table b {
+  id integer [pk]
+}
+
+Ref: a.id <> b.id
+Ref: missing.id > b.id
+TableGroup g { a }
+`,
+			want: []model.Table{
+				{Name: "a", Columns: []model.Column{
+					{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+					{Name: "b_id", Type: "INTEGER"},
+				}},
+				{Name: "b", Columns: []model.Column{{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true}}},
+			},
+			warnings: []string{
+				"line 12: TableGroup element ignored",
+				"line 10: many-to-many ref between a and b ignored",
+				"line 11: ref from unknown table missing ignored",
+			},
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+type ImportFormat string
+
+const (
+	FormatDBML     ImportFormat = "dbml"
+	FormatPostgres ImportFormat = "postgres"
+	FormatMySQL    ImportFormat = "mysql"
+)
+
+// Result is the schema recovered from a script, along with anything in the
+// input that could not be represented and was skipped
+type Result struct {
+	Data     model.SchemaData `json:"data"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+func Import(input string, format ImportFormat) (*Result, error) {
+	switch format {
+	case FormatDBML:
+		return importDBML(input)
+	case FormatPostgres:
+		return importPostgres(input)
+	case FormatMySQL:
+		return importMySQL(input)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// warnf records a warning on the result
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// applyTypeArgs stores the numeric arguments of a type, as in varchar(50) or
+// decimal(10, 2), on the column according to what its type takes. Arguments
+// such as the display width of int(11) carry no meaning and are dropped.
+func applyTypeArgs(col *model.Column, args []int) {
+	if len(args) == 0 {
+		return
+	}
+	switch strings.ToUpper(col.Type) {
+	case "VARCHAR", "CHAR", "BINARY", "VARBINARY":
+		col.Length = args[0]
+	case "DECIMAL", "NUMERIC":
+		col.Precision = args[0]
+		if len(args) > 1 {
+			col.Scale = args[1]
+		}
+	case "TIME", "TIMETZ", "TIMESTAMP", "TIMESTAMPTZ", "DATETIME":
+		col.Precision = args[0]
+	}
+}
+
+// findTable returns a pointer to the named table, or nil when it does not exist
+func findTable(data *model.SchemaData, name string) *model.Table {
+	for i := range data.Tables {
+		if data.Tables[i].Name == name {
+			return &data.Tables[i]
+		}
+	}
+	return nil
+}
+
+// findColumn returns a pointer to the named column, or nil when it does not exist
+func findColumn(table *model.Table, name string) *model.Column {
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			return &table.Columns[i]
+		}
+	}
+	return nil
+}
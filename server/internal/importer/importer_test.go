@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// importTest is one input to a parser with the schema and warnings it
+// should produce
+type importTest struct {
+	name     string
+	input    string
+	want     []model.Table
+	warnings []string
+}
+
+func runImportTests(t *testing.T, format ImportFormat, tests []importTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Import(tt.input, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Data.Tables, tt.want) {
+				got, _ := json.MarshalIndent(result.Data.Tables, "", "  ")
+				want, _ := json.MarshalIndent(tt.want, "", "  ")
+				t.Errorf("tables:\n%s\nwant:\n%s", got, want)
+			}
+			if !reflect.DeepEqual(result.Warnings, tt.warnings) {
+				t.Errorf("warnings:\n%q\nwant:\n%q", result.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestImportErrors(t *testing.T) {
+	for _, tt := range []struct {
+		format ImportFormat
+		input  string
+	}{
+		{FormatPostgres, "CREATE TABLE a (name text DEFAULT 'x);"},
+		{FormatMySQL, "CREATE TABLE `a (id int);"},
+		{FormatDBML, "Table a {\n  id int [note: 'x]\n}"},
+		{FormatDBML, "Table a {\n  id int\n"},
+		{"oracle", "CREATE TABLE a (id int);"},
+	} {
+		if result, err := Import(tt.input, tt.format); err == nil {
+			t.Errorf("%s %q: no error, got %+v", tt.format, tt.input, result)
+		}
+	}
+}
@@ -0,0 +1,584 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+var mysqlDialect = sqlDialect{identQuote: '`', hashComments: true, backslashEscapes: true}
+
+// mysqlColumnStop lists the words that end a DEFAULT expression
+var mysqlColumnStop = map[string]bool{
+	"not": true, "null": true, "default": true, "auto_increment": true, "unique": true,
+	"primary": true, "key": true, "comment": true, "collate": true, "character": true,
+	"charset": true, "references": true, "check": true, "constraint": true, "on": true,
+	"generated": true, "as": true, "invisible": true, "visible": true,
+	"column_format": true, "storage": true, "srid": true,
+}
+
+type mysqlParser struct {
+	result *Result
+}
+
+func importMySQL(input string) (*Result, error) {
+	p := &mysqlParser{
+		result: &Result{Data: model.SchemaData{Tables: []model.Table{}}},
+	}
+
+	tokens, err := lexSQL(p.stripDelimiterBlocks(input), mysqlDialect)
+	if err != nil {
+		return nil, err
+	}
+
+	// A statement that cannot be understood is skipped with a warning so the
+	// rest of the dump still comes through
+	for _, stmt := range splitStatements(tokens) {
+		if err := p.parseStatement(&sqlCursor{tokens: stmt}); err != nil {
+			p.result.warnf("statement skipped: %v", err)
+		}
+	}
+
+	resolveImplicitReferences(p.result)
+	return p.result, nil
+}
+
+// stripDelimiterBlocks blanks out statements written under a custom DELIMITER,
+// which mysqldump uses for stored routines and triggers. Lines are kept so
+// that later line numbers stay accurate.
+func (p *mysqlParser) stripDelimiterBlocks(input string) string {
+	lines := strings.Split(input, "\n")
+	delimiter := ";"
+	blockStart := 0
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		fields := strings.Fields(trimmed)
+
+		if len(fields) == 2 && strings.EqualFold(fields[0], "DELIMITER") {
+			delimiter = fields[1]
+			lines[i] = ""
+			continue
+		}
+		if delimiter == ";" {
+			continue
+		}
+
+		if trimmed != "" && blockStart == 0 {
+			blockStart = i + 1
+		}
+		lines[i] = ""
+		if strings.HasSuffix(trimmed, delimiter) {
+			p.result.warnf("line %d: routine or trigger definition ignored", blockStart)
+			blockStart = 0
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (p *mysqlParser) parseStatement(c *sqlCursor) error {
+	line := c.peek().line
+
+	switch {
+	case c.acceptKeyword("create", "table"), c.acceptKeyword("create", "temporary", "table"):
+		return p.parseCreateTable(c)
+	case c.acceptKeyword("alter", "table"):
+		return p.parseAlterTable(c)
+	case c.isKeyword("create", "index"), c.isKeyword("create", "unique", "index"),
+		c.isKeyword("create", "fulltext", "index"), c.isKeyword("create", "spatial", "index"):
+		c.next()
+		return p.parseCreateIndex(c)
+	case c.isKeyword("set"), c.isKeyword("lock", "tables"), c.isKeyword("unlock", "tables"),
+		c.isKeyword("drop"), c.isKeyword("use"), c.isKeyword("create", "database"),
+		c.isKeyword("create", "schema"), c.isKeyword("start", "transaction"),
+		c.isKeyword("begin"), c.isKeyword("commit"), c.isKeyword("insert"), c.isKeyword("replace"):
+		// mysqldump session setup and data, with no bearing on the schema design
+		return nil
+	default:
+		var words []string
+		for i := 0; i < 2 && c.peekAt(i).kind == sqlIdent; i++ {
+			words = append(words, strings.ToUpper(c.peekAt(i).text))
+		}
+		p.result.warnf("line %d: unsupported statement %s ignored", line, strings.Join(words, " "))
+		return nil
+	}
+}
+
+func (p *mysqlParser) parseCreateTable(c *sqlCursor) error {
+	line := c.peek().line
+	c.acceptKeyword("if", "not", "exists")
+
+	name, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if findTable(&p.result.Data, name) != nil {
+		return fmt.Errorf("line %d: duplicate table %s", line, name)
+	}
+	if !c.isPunct("(") {
+		p.result.warnf("line %d: table %s is not defined by columns and was ignored", line, name)
+		return nil
+	}
+
+	inner, err := c.parenthesized()
+	if err != nil {
+		return err
+	}
+
+	table := model.Table{Name: name, Columns: []model.Column{}}
+	for _, element := range splitTopLevel(inner) {
+		ec := &sqlCursor{tokens: element}
+		if ec.done() {
+			continue
+		}
+		if atMySQLTableConstraint(ec, 0) {
+			if err := p.parseTableConstraint(ec, &table); err != nil {
+				return err
+			}
+			continue
+		}
+		col, err := p.parseColumn(ec, &table)
+		if err != nil {
+			return err
+		}
+		table.Columns = append(table.Columns, *col)
+	}
+
+	// Table options: only the engine and comment are part of the model
+	for !c.done() {
+		switch {
+		case c.acceptKeyword("engine"):
+			c.acceptPunct("=")
+			table.Engine = c.next().text
+		case c.acceptKeyword("comment"):
+			c.acceptPunct("=")
+			table.Comment = c.next().text
+		default:
+			c.next()
+		}
+	}
+
+	p.result.Data.Tables = append(p.result.Data.Tables, table)
+	return nil
+}
+
+func (p *mysqlParser) parseColumn(c *sqlCursor, table *model.Table) (*model.Column, error) {
+	t := c.next()
+	if t.kind != sqlIdent && t.kind != sqlQuotedIdent {
+		return nil, fmt.Errorf("line %d: expected column name, got %q", t.line, t.text)
+	}
+	col := &model.Column{Name: t.text}
+
+	typeTok := c.next()
+	if typeTok.kind != sqlIdent {
+		return nil, fmt.Errorf("line %d: column %s has no type", t.line, col.Name)
+	}
+	typeName := strings.ToLower(typeTok.text)
+	if typeName == "double" {
+		c.acceptKeyword("precision")
+	}
+	if typeName == "national" || (typeName == "character" && c.isKeyword("varying")) {
+		typeName = strings.ToLower(c.next().text)
+	}
+
+	var args []sqlToken
+	if c.isPunct("(") {
+		var err error
+		if args, err = c.parenthesized(); err != nil {
+			return nil, err
+		}
+	}
+	col.Type, col.EnumValues = normalizeMySQLType(typeName, args)
+	if col.EnumValues == nil {
+		applyTypeArgs(col, numericArgs(args))
+	}
+
+	if typeName == "serial" {
+		// SERIAL is an alias for BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE
+		col.Unsigned, col.NotNull, col.AutoIncrement, col.Unique = true, true, true, true
+	}
+
+	if err := p.parseColumnConstraints(c, table, col); err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+func (p *mysqlParser) parseColumnConstraints(c *sqlCursor, table *model.Table, col *model.Column) error {
+	constraintName := ""
+	for !c.done() {
+		line := c.peek().line
+		switch {
+		case c.acceptKeyword("not", "null"):
+			col.NotNull = true
+		case c.acceptKeyword("null"):
+			col.NotNull = false
+		case c.acceptKeyword("default"):
+			start := c.pos
+			c.skipExpression(mysqlColumnStop)
+			// mysqldump spells out DEFAULT NULL on every nullable column
+			if def := literalDefault(c.tokens[start:c.pos], mysqlDialect); !strings.EqualFold(def, "null") {
+				col.Default = &def
+			}
+		case c.acceptKeyword("auto_increment"):
+			col.AutoIncrement = true
+		case c.acceptKeyword("unique"):
+			c.acceptKeyword("key")
+			col.Unique = true
+		case c.acceptKeyword("primary", "key"), c.acceptKeyword("key"):
+			col.PrimaryKey = true
+			col.NotNull = true
+		case c.acceptKeyword("unsigned"):
+			col.Unsigned = true
+		case c.acceptKeyword("signed"), c.acceptKeyword("zerofill"),
+			c.acceptKeyword("invisible"), c.acceptKeyword("visible"):
+		case c.acceptKeyword("character", "set"), c.acceptKeyword("charset"), c.acceptKeyword("collate"),
+			c.acceptKeyword("column_format"), c.acceptKeyword("storage"), c.acceptKeyword("srid"):
+			c.next()
+		case c.acceptKeyword("comment"):
+			col.Comment = c.next().text
+		case c.acceptKeyword("on", "update"):
+			c.next()
+			if c.isPunct("(") {
+				c.parenthesized()
+			}
+		case c.acceptKeyword("generated", "always", "as"), c.acceptKeyword("as"):
+			if _, err := c.parenthesized(); err != nil {
+				return err
+			}
+			if !c.acceptKeyword("virtual") {
+				c.acceptKeyword("stored")
+			}
+			p.result.warnf("line %d: generated column %s.%s imported as a plain column", line, table.Name, col.Name)
+		case c.acceptKeyword("references"):
+			fk, err := parseReferences(c, "", []string{col.Name})
+			if err != nil {
+				return err
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		case c.acceptKeyword("constraint"):
+			if !c.isKeyword("check") {
+				constraintName = c.next().text
+			}
+		case c.acceptKeyword("check"):
+			check, err := parseCheck(c, constraintName, mysqlDialect)
+			if err != nil {
+				return err
+			}
+			table.Checks = append(table.Checks, check)
+			constraintName = ""
+			c.acceptKeyword("not")
+			c.acceptKeyword("enforced")
+		default:
+			t := c.next()
+			return fmt.Errorf("line %d: unexpected %q in column %s", t.line, t.text, col.Name)
+		}
+	}
+	return nil
+}
+
+// atMySQLTableConstraint reports whether a table-level key or constraint
+// starts at the given offset
+func atMySQLTableConstraint(c *sqlCursor, offset int) bool {
+	t := c.peekAt(offset)
+	if t.kind != sqlIdent {
+		return false
+	}
+	switch strings.ToLower(t.text) {
+	case "constraint", "primary", "unique", "key", "index", "fulltext", "spatial", "foreign", "check":
+		return true
+	}
+	return false
+}
+
+// skipIndexName skips the optional name and USING clause in front of a key's
+// column list
+func (c *sqlCursor) skipIndexName() {
+	if !c.isPunct("(") && !c.isKeyword("using") {
+		c.next()
+	}
+	if c.acceptKeyword("using") {
+		c.next()
+	}
+}
+
+func (p *mysqlParser) parseTableConstraint(c *sqlCursor, table *model.Table) error {
+	line := c.peek().line
+	name := ""
+	if c.acceptKeyword("constraint") {
+		if !c.isKeyword("primary") && !c.isKeyword("unique") && !c.isKeyword("foreign") && !c.isKeyword("check") {
+			name = c.next().text
+		}
+	}
+
+	switch {
+	case c.acceptKeyword("primary", "key"):
+		if c.acceptKeyword("using") {
+			c.next()
+		}
+		cols, err := c.nameList()
+		if err != nil {
+			return err
+		}
+		for _, name := range cols {
+			if col := findColumn(table, name); col != nil {
+				col.PrimaryKey = true
+				col.NotNull = true
+			} else {
+				p.result.warnf("line %d: primary key on unknown column %s.%s ignored", line, table.Name, name)
+			}
+		}
+
+	case c.acceptKeyword("unique"):
+		if !c.acceptKeyword("key") {
+			c.acceptKeyword("index")
+		}
+		idx, ok, err := parseMySQLIndex(c, model.Index{Name: name, Unique: true})
+		if err != nil {
+			return err
+		}
+		switch {
+		case !ok:
+			p.result.warnf("line %d: expression unique key on table %s ignored", line, table.Name)
+		case len(idx.Columns) == 1 && idx.Method == "" && findColumn(table, idx.Columns[0].Name) != nil:
+			findColumn(table, idx.Columns[0].Name).Unique = true
+		case idx.Method == "" && !hasDescending(idx):
+			// Plain unique keys are the MySQL form of UNIQUE constraints
+			table.Uniques = append(table.Uniques, model.Unique{Name: idx.Name, Columns: idx.ColumnNames()})
+		default:
+			table.Indexes = append(table.Indexes, idx)
+		}
+
+	case c.acceptKeyword("foreign", "key"):
+		c.skipIndexName()
+		cols, err := c.nameList()
+		if err != nil {
+			return err
+		}
+		if !c.acceptKeyword("references") {
+			return fmt.Errorf("line %d: expected REFERENCES in foreign key on %s", line, table.Name)
+		}
+		fk, err := parseReferences(c, name, cols)
+		if err != nil {
+			return err
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+
+	case c.acceptKeyword("check"):
+		check, err := parseCheck(c, name, mysqlDialect)
+		if err != nil {
+			return err
+		}
+		table.Checks = append(table.Checks, check)
+		c.acceptKeyword("not")
+		c.acceptKeyword("enforced")
+
+	default:
+		// KEY, INDEX, FULLTEXT and SPATIAL indexes
+		var idx model.Index
+		switch {
+		case c.acceptKeyword("fulltext"):
+			idx.Method = "fulltext"
+		case c.acceptKeyword("spatial"):
+			idx.Method = "spatial"
+		}
+		if !c.acceptKeyword("key") {
+			c.acceptKeyword("index")
+		}
+		idx, ok, err := parseMySQLIndex(c, idx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			p.result.warnf("line %d: expression index on table %s ignored", line, table.Name)
+			return nil
+		}
+		table.Indexes = append(table.Indexes, idx)
+	}
+	return nil
+}
+
+func hasDescending(idx model.Index) bool {
+	for _, col := range idx.Columns {
+		if col.IsDescending() {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMySQLIndex reads the optional name, USING clauses and column list of
+// an index into idx; ok is false for functional indexes
+func parseMySQLIndex(c *sqlCursor, idx model.Index) (model.Index, bool, error) {
+	if !c.isPunct("(") && !c.isKeyword("using") {
+		idx.Name = c.next().text
+	}
+	if c.acceptKeyword("using") {
+		idx.Method = strings.ToLower(c.next().text)
+	}
+	cols, ok, err := c.indexColumnList()
+	if err != nil || !ok {
+		return idx, ok, err
+	}
+	idx.Columns = cols
+	if c.acceptKeyword("using") {
+		idx.Method = strings.ToLower(c.next().text)
+	}
+	return idx, true, nil
+}
+
+// parseCreateIndex handles CREATE [UNIQUE | FULLTEXT | SPATIAL] INDEX
+func (p *mysqlParser) parseCreateIndex(c *sqlCursor) error {
+	line := c.peek().line
+	var idx model.Index
+	switch {
+	case c.acceptKeyword("unique"):
+		idx.Unique = true
+	case c.acceptKeyword("fulltext"):
+		idx.Method = "fulltext"
+	case c.acceptKeyword("spatial"):
+		idx.Method = "spatial"
+	}
+	if !c.acceptKeyword("index") {
+		return fmt.Errorf("line %d: expected INDEX", line)
+	}
+
+	idx.Name = c.next().text
+	if c.acceptKeyword("using") {
+		idx.Method = strings.ToLower(c.next().text)
+	}
+	if !c.acceptKeyword("on") {
+		return fmt.Errorf("line %d: expected ON in CREATE INDEX", line)
+	}
+	tableName, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table := findTable(&p.result.Data, tableName)
+	if table == nil {
+		p.result.warnf("line %d: index on unknown table %s ignored", line, tableName)
+		return nil
+	}
+
+	idx, ok, err := parseMySQLIndex(c, idx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		p.result.warnf("line %d: expression index on table %s ignored", line, table.Name)
+		return nil
+	}
+	table.Indexes = append(table.Indexes, idx)
+	return nil
+}
+
+func (p *mysqlParser) parseAlterTable(c *sqlCursor) error {
+	line := c.peek().line
+	name, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table := findTable(&p.result.Data, name)
+	if table == nil {
+		p.result.warnf("line %d: ALTER TABLE on unknown table %s ignored", line, name)
+		return nil
+	}
+
+	for _, action := range splitTopLevel(c.tokens[c.pos:]) {
+		ac := &sqlCursor{tokens: action}
+		actionLine := ac.peek().line
+
+		switch {
+		case ac.isKeyword("add") && atMySQLTableConstraint(ac, 1):
+			ac.next()
+			if err := p.parseTableConstraint(ac, table); err != nil {
+				return err
+			}
+
+		case ac.acceptKeyword("add"):
+			ac.acceptKeyword("column")
+			col, err := p.parseColumn(ac, table)
+			if err != nil {
+				return err
+			}
+			table.Columns = append(table.Columns, *col)
+
+		case ac.acceptKeyword("modify"), ac.acceptKeyword("change"):
+			isChange := strings.EqualFold(action[0].text, "change")
+			ac.acceptKeyword("column")
+			oldName := ac.peek().text
+			if isChange {
+				ac.next()
+			}
+			col, err := p.parseColumn(ac, table)
+			if err != nil {
+				return err
+			}
+			existing := findColumn(table, oldName)
+			if existing == nil {
+				p.result.warnf("line %d: %s of unknown column %s.%s ignored", actionLine, strings.ToUpper(action[0].text), name, oldName)
+				continue
+			}
+			// Keys added by earlier ALTER statements survive the redefinition
+			col.PrimaryKey = col.PrimaryKey || existing.PrimaryKey
+			col.Unique = col.Unique || existing.Unique
+			col.NotNull = col.NotNull || col.PrimaryKey
+			*existing = *col
+
+		case ac.acceptKeyword("engine"):
+			ac.acceptPunct("=")
+			table.Engine = ac.next().text
+
+		case ac.acceptKeyword("comment"):
+			ac.acceptPunct("=")
+			table.Comment = ac.next().text
+
+		case ac.isKeyword("auto_increment"), ac.isKeyword("default", "charset"),
+			ac.isKeyword("default", "character"), ac.isKeyword("character", "set"),
+			ac.isKeyword("collate"),
+			ac.isKeyword("disable", "keys"), ac.isKeyword("enable", "keys"):
+			// Table options outside the model
+
+		default:
+			p.result.warnf("line %d: ALTER TABLE %s action ignored", actionLine, name)
+		}
+	}
+	return nil
+}
+
+// normalizeMySQLType maps a MySQL type and its arguments onto the designer's
+// type names, extracting the values of ENUM and SET types
+func normalizeMySQLType(name string, args []sqlToken) (string, []string) {
+	switch name {
+	case "enum", "set":
+		var values []string
+		for _, t := range args {
+			if t.kind == sqlString {
+				values = append(values, t.text)
+			}
+		}
+		return strings.ToUpper(name), values
+	case "tinyint":
+		// tinyint(1) is how MySQL spells a boolean
+		if len(args) == 1 && args[0].text == "1" {
+			return "BOOLEAN", nil
+		}
+		return "TINYINT", nil
+	case "bool", "boolean":
+		return "BOOLEAN", nil
+	case "integer":
+		return "INT", nil
+	case "serial":
+		return "BIGINT", nil
+	case "real":
+		return "DOUBLE", nil
+	case "dec", "numeric", "fixed":
+		return "DECIMAL", nil
+	case "character":
+		return "CHAR", nil
+	default:
+		return strings.ToUpper(name), nil
+	}
+}
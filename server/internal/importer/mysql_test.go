@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestImportMySQL(t *testing.T) {
+	runImportTests(t, FormatMySQL, []importTest{
+		{
+			name: "mysqldump output",
+			input: "-- MySQL dump 10.13\n" +
+				"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+				"SET NAMES utf8mb4;\n" +
+				"DROP TABLE IF EXISTS `users`;\n" +
+				"CREATE TABLE `users` (\n" +
+				"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `email` varchar(255) NOT NULL,\n" +
+				"  `role` enum('admin','it''s me') DEFAULT 'admin' COMMENT 'access level',\n" +
+				"  `price` decimal(10,2) NOT NULL DEFAULT '0.00',\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `users_email` (`email`),\n" +
+				"  KEY `users_role` (`role`),\n" +
+				"  CONSTRAINT `price_positive` CHECK ((`price` >= 0))\n" +
+				") ENGINE=MyISAM AUTO_INCREMENT=5 DEFAULT CHARSET=utf8mb4 COMMENT='people';\n" +
+				"LOCK TABLES `users` WRITE;\n" +
+				"INSERT INTO `users` VALUES (1,'a','admin',1.00);\n" +
+				"UNLOCK TABLES;\n",
+			want: []model.Table{{
+				Name: "users",
+				Columns: []model.Column{
+					{Name: "id", Type: "INT", Unsigned: true, PrimaryKey: true, NotNull: true, AutoIncrement: true},
+					{Name: "email", Type: "VARCHAR", Length: 255, NotNull: true, Unique: true},
+					{Name: "role", Type: "ENUM", Default: ptr("admin"), EnumValues: []string{"admin", "it's me"}, Comment: "access level"},
+					{Name: "price", Type: "DECIMAL", Precision: 10, Scale: 2, NotNull: true, Default: ptr("0.00")},
+				},
+				Indexes: []model.Index{{Name: "users_role", Columns: []model.IndexColumn{{Name: "role"}}}},
+				Checks:  []model.Check{{Name: "price_positive", Expression: "price >= 0"}},
+				Engine:  "MyISAM",
+				Comment: "people",
+			}},
+		},
+		{
+			name: "foreign keys",
+			input: "CREATE TABLE users (id int PRIMARY KEY);\n" +
+				"CREATE TABLE `orders` (\n" +
+				"  `id` bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,\n" +
+				"  `user_id` int unsigned DEFAULT NULL,\n" +
+				"  CONSTRAINT `orders_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL ON UPDATE CASCADE\n" +
+				") ENGINE=InnoDB;\n",
+			want: []model.Table{
+				{
+					Name:    "users",
+					Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true, NotNull: true}},
+				},
+				{
+					Name: "orders",
+					Columns: []model.Column{
+						{Name: "id", Type: "BIGINT", PrimaryKey: true, NotNull: true, AutoIncrement: true},
+						{Name: "user_id", Type: "INT", Unsigned: true},
+					},
+					ForeignKeys: []model.ForeignKey{{
+						Name:       "orders_user",
+						Column:     "user_id",
+						References: model.Reference{Table: "users", Column: "id"},
+						OnDelete:   "SET NULL",
+						OnUpdate:   "CASCADE",
+					}},
+					Engine: "InnoDB",
+				},
+			},
+		},
+		{
+			name: "unsupported statements are warnings",
+			input: "CREATE TABLE a (id int;\n" +
+				"CREATE TABLE b (id int PRIMARY KEY);\n" +
+				"GRANT ALL ON b TO x;\n" +
+				"DELIMITER ;;\n" +
+				"CREATE TRIGGER t BEFORE INSERT ON b FOR EACH ROW BEGIN SET NEW.id = 1; END;;\n" +
+				"DELIMITER ;\n" +
+				"CREATE VIEW v AS SELECT 1;\n" +
+				"ALTER TABLE missing ADD COLUMN c int;\n",
+			want: []model.Table{{
+				Name:    "b",
+				Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true, NotNull: true}},
+			}},
+			warnings: []string{
+				// Routines are set aside before the statements are parsed
+				"line 5: routine or trigger definition ignored",
+				"statement skipped: line 1: unbalanced parentheses",
+				"line 3: unsupported statement GRANT ALL ignored",
+				"line 7: unsupported statement CREATE VIEW ignored",
+				"line 8: ALTER TABLE on unknown table missing ignored",
+			},
+		},
+	})
+}
@@ -0,0 +1,620 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// pgColumnStop lists the words that end a column type and start its constraints
+var pgColumnStop = map[string]bool{
+	"not": true, "null": true, "default": true, "primary": true, "unique": true,
+	"references": true, "check": true, "constraint": true, "collate": true,
+	"generated": true, "deferrable": true, "initially": true,
+}
+
+type pgParser struct {
+	result *Result
+	enums  map[string][]string
+}
+
+func importPostgres(input string) (*Result, error) {
+	tokens, err := lexSQL(input, postgresDialect)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pgParser{
+		result: &Result{Data: model.SchemaData{Tables: []model.Table{}}},
+		enums:  make(map[string][]string),
+	}
+
+	for _, stmt := range splitStatements(tokens) {
+		if err := p.parseStatement(&sqlCursor{tokens: stmt}); err != nil {
+			return nil, err
+		}
+	}
+	p.resolve()
+
+	return p.result, nil
+}
+
+func (p *pgParser) parseStatement(c *sqlCursor) error {
+	line := c.peek().line
+
+	switch {
+	case c.acceptKeyword("create", "table"), c.acceptKeyword("create", "unlogged", "table"):
+		return p.parseCreateTable(c)
+	case c.acceptKeyword("create", "type"):
+		return p.parseCreateType(c)
+	case c.acceptKeyword("create", "index"):
+		return p.parseCreateIndex(c, false)
+	case c.acceptKeyword("create", "unique", "index"):
+		return p.parseCreateIndex(c, true)
+	case c.acceptKeyword("alter", "table"):
+		return p.parseAlterTable(c)
+	case c.acceptKeyword("comment", "on"):
+		return p.parseComment(c)
+	case c.isKeyword("set"), c.isKeyword("select"),
+		c.isKeyword("create", "sequence"), c.isKeyword("alter", "sequence"),
+		c.isKeyword("create", "schema"), c.isKeyword("create", "extension"),
+		c.isKeyword("begin"), c.isKeyword("commit"), c.isKeyword("grant"), c.isKeyword("revoke"),
+		c.isKeyword("alter", "schema"), c.isKeyword("alter", "type"), c.isKeyword("alter", "function"):
+		// pg_dump bookkeeping with no bearing on the schema design
+		return nil
+	default:
+		var words []string
+		for i := 0; i < 2 && c.peekAt(i).kind == sqlIdent; i++ {
+			words = append(words, strings.ToUpper(c.peekAt(i).text))
+		}
+		p.result.warnf("line %d: unsupported statement %s ignored", line, strings.Join(words, " "))
+		return nil
+	}
+}
+
+func (p *pgParser) parseCreateType(c *sqlCursor) error {
+	line := c.peek().line
+	name, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if !c.acceptKeyword("as", "enum") {
+		p.result.warnf("line %d: type %s ignored, only enum types are supported", line, name)
+		return nil
+	}
+
+	inner, err := c.parenthesized()
+	if err != nil {
+		return err
+	}
+	var values []string
+	for _, t := range inner {
+		if t.kind == sqlString {
+			values = append(values, t.text)
+		}
+	}
+	p.enums[strings.ToLower(name)] = values
+	return nil
+}
+
+// parseComment handles COMMENT ON TABLE and COMMENT ON COLUMN; comments on
+// other objects are not part of the model
+func (p *pgParser) parseComment(c *sqlCursor) error {
+	line := c.peek().line
+	isColumn := c.acceptKeyword("column")
+	if !isColumn && !c.acceptKeyword("table") {
+		return nil
+	}
+
+	var parts []string
+	for {
+		t := c.next()
+		if t.kind != sqlIdent && t.kind != sqlQuotedIdent {
+			return fmt.Errorf("line %d: expected name in COMMENT ON, got %q", t.line, t.text)
+		}
+		parts = append(parts, t.text)
+		if !c.acceptPunct(".") {
+			break
+		}
+	}
+	if !c.acceptKeyword("is") {
+		return fmt.Errorf("line %d: expected IS in COMMENT ON", line)
+	}
+	comment := ""
+	if t := c.next(); t.kind == sqlString {
+		comment = t.text
+	}
+
+	if isColumn {
+		if len(parts) < 2 {
+			return fmt.Errorf("line %d: COMMENT ON COLUMN needs table.column", line)
+		}
+		tableName, colName := parts[len(parts)-2], parts[len(parts)-1]
+		table := findTable(&p.result.Data, tableName)
+		if table == nil || findColumn(table, colName) == nil {
+			p.result.warnf("line %d: comment on unknown column %s.%s ignored", line, tableName, colName)
+			return nil
+		}
+		findColumn(table, colName).Comment = comment
+		return nil
+	}
+
+	table := findTable(&p.result.Data, parts[len(parts)-1])
+	if table == nil {
+		p.result.warnf("line %d: comment on unknown table %s ignored", line, parts[len(parts)-1])
+		return nil
+	}
+	table.Comment = comment
+	return nil
+}
+
+func (p *pgParser) parseCreateIndex(c *sqlCursor, unique bool) error {
+	line := c.peek().line
+	idx := model.Index{Unique: unique}
+
+	c.acceptKeyword("concurrently")
+	c.acceptKeyword("if", "not", "exists")
+	if !c.isKeyword("on") {
+		name, err := c.qualifiedName()
+		if err != nil {
+			return err
+		}
+		idx.Name = name
+	}
+	if !c.acceptKeyword("on") {
+		return fmt.Errorf("line %d: expected ON in CREATE INDEX", line)
+	}
+	c.acceptKeyword("only")
+	tableName, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table := findTable(&p.result.Data, tableName)
+	if table == nil {
+		p.result.warnf("line %d: index on unknown table %s ignored", line, tableName)
+		return nil
+	}
+
+	// btree is the default, and pg_dump spells it out on every index
+	if c.acceptKeyword("using") {
+		if method := strings.ToLower(c.next().text); method != "btree" {
+			idx.Method = method
+		}
+	}
+
+	cols, ok, err := c.indexColumnList()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		p.result.warnf("line %d: expression index on table %s ignored", line, table.Name)
+		return nil
+	}
+	idx.Columns = cols
+
+	if c.acceptKeyword("include") {
+		if idx.Include, err = c.nameList(); err != nil {
+			return err
+		}
+	}
+	if !c.acceptKeyword("nulls", "not", "distinct") {
+		c.acceptKeyword("nulls", "distinct")
+	}
+	if c.acceptKeyword("with") {
+		if _, err := c.parenthesized(); err != nil {
+			return err
+		}
+	}
+	if c.acceptKeyword("tablespace") {
+		c.next()
+	}
+	if c.acceptKeyword("where") {
+		idx.Where = renderSQL(c.tokens[c.pos:], postgresDialect)
+	}
+
+	table.Indexes = append(table.Indexes, idx)
+	return nil
+}
+
+func (p *pgParser) parseCreateTable(c *sqlCursor) error {
+	line := c.peek().line
+	c.acceptKeyword("if", "not", "exists")
+
+	name, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if findTable(&p.result.Data, name) != nil {
+		return fmt.Errorf("line %d: duplicate table %s", line, name)
+	}
+
+	if c.isKeyword("partition", "of") || c.isKeyword("of") {
+		p.result.warnf("line %d: table %s is a partition or typed table and was ignored", line, name)
+		return nil
+	}
+
+	inner, err := c.parenthesized()
+	if err != nil {
+		return err
+	}
+
+	p.result.Data.Tables = append(p.result.Data.Tables, model.Table{Name: name, Columns: []model.Column{}})
+	table := &p.result.Data.Tables[len(p.result.Data.Tables)-1]
+
+	for _, element := range splitTopLevel(inner) {
+		ec := &sqlCursor{tokens: element}
+		if ec.done() {
+			continue
+		}
+		if atTableConstraint(ec, 0) {
+			if err := p.parseTableConstraint(ec, table); err != nil {
+				return err
+			}
+			continue
+		}
+		if ec.isKeyword("like") {
+			p.result.warnf("line %d: LIKE clause in table %s ignored", ec.peek().line, name)
+			continue
+		}
+		if err := p.parseColumn(ec, table); err != nil {
+			return err
+		}
+	}
+
+	// Trailing INHERITS, PARTITION BY, WITH and TABLESPACE clauses are not modelled
+	return nil
+}
+
+func (p *pgParser) parseColumn(c *sqlCursor, table *model.Table) error {
+	t := c.next()
+	if t.kind != sqlIdent && t.kind != sqlQuotedIdent {
+		return fmt.Errorf("line %d: expected column name, got %q", t.line, t.text)
+	}
+	col := model.Column{Name: t.text}
+
+	// The type runs until the first constraint keyword
+	var words []string
+	var args []int
+	for !c.done() {
+		next := c.peek()
+		if next.kind == sqlIdent && pgColumnStop[strings.ToLower(next.text)] {
+			break
+		}
+		if next.kind == sqlPunct && next.text == "(" {
+			inner, err := c.parenthesized()
+			if err != nil {
+				return err
+			}
+			if args == nil {
+				args = numericArgs(inner)
+			}
+			continue
+		}
+		if next.kind == sqlPunct && next.text == "[" {
+			c.next()
+			c.acceptPunct("]")
+			words = append(words, "[]")
+			continue
+		}
+		if next.kind == sqlPunct && next.text == "." {
+			// Schema-qualified type: keep only the type name
+			c.next()
+			words = words[:0]
+			continue
+		}
+		words = append(words, c.next().text)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("line %d: column %s has no type", t.line, col.Name)
+	}
+	col.Type, col.AutoIncrement = normalizePostgresType(words)
+	applyTypeArgs(&col, args)
+
+	if err := p.parseColumnConstraints(c, table, &col); err != nil {
+		return err
+	}
+
+	table.Columns = append(table.Columns, col)
+	return nil
+}
+
+func (p *pgParser) parseColumnConstraints(c *sqlCursor, table *model.Table, col *model.Column) error {
+	constraintName := ""
+	for !c.done() {
+		line := c.peek().line
+		switch {
+		case c.acceptKeyword("constraint"):
+			constraintName = c.next().text
+			continue
+		case c.acceptKeyword("not", "null"):
+			col.NotNull = true
+		case c.acceptKeyword("null"):
+			col.NotNull = false
+		case c.acceptKeyword("primary", "key"):
+			col.PrimaryKey = true
+			col.NotNull = true
+		case c.acceptKeyword("unique"):
+			col.Unique = true
+			c.acceptKeyword("nulls", "not", "distinct")
+			c.acceptKeyword("nulls", "distinct")
+		case c.acceptKeyword("default"):
+			start := c.pos
+			c.skipExpression(pgColumnStop)
+			p.applyDefault(col, c.tokens[start:c.pos])
+		case c.acceptKeyword("generated"):
+			start := c.pos
+			c.skipExpression(pgColumnStop)
+			if strings.Contains(strings.ToLower(renderSQL(c.tokens[start:c.pos], postgresDialect)), "identity") {
+				col.AutoIncrement = true
+				col.NotNull = true
+			} else {
+				p.result.warnf("line %d: generated column %s.%s imported as a plain column", line, table.Name, col.Name)
+			}
+		case c.acceptKeyword("references"):
+			fk, err := parseReferences(c, constraintName, []string{col.Name})
+			if err != nil {
+				return err
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		case c.acceptKeyword("check"):
+			check, err := parseCheck(c, constraintName, postgresDialect)
+			if err != nil {
+				return err
+			}
+			table.Checks = append(table.Checks, check)
+			c.acceptKeyword("no", "inherit")
+		case c.acceptKeyword("collate"):
+			c.qualifiedName()
+		case c.acceptKeyword("deferrable"), c.acceptKeyword("not", "deferrable"),
+			c.acceptKeyword("initially", "deferred"), c.acceptKeyword("initially", "immediate"):
+		default:
+			t := c.next()
+			return fmt.Errorf("line %d: unexpected %q in column %s", t.line, t.text, col.Name)
+		}
+		// A constraint name only applies to the clause right after it
+		constraintName = ""
+	}
+	return nil
+}
+
+func (p *pgParser) applyDefault(col *model.Column, expr []sqlToken) {
+	rendered := strings.ToLower(renderSQL(expr, postgresDialect))
+	if strings.HasPrefix(rendered, "nextval(") {
+		col.AutoIncrement = true
+		return
+	}
+	def := literalDefault(expr, postgresDialect)
+	col.Default = &def
+}
+
+// atTableConstraint reports whether a table constraint starts at the given offset
+func atTableConstraint(c *sqlCursor, offset int) bool {
+	t := c.peekAt(offset)
+	if t.kind != sqlIdent {
+		return false
+	}
+	switch strings.ToLower(t.text) {
+	case "constraint", "primary", "unique", "foreign", "check", "exclude":
+		return true
+	}
+	return false
+}
+
+func (p *pgParser) parseTableConstraint(c *sqlCursor, table *model.Table) error {
+	line := c.peek().line
+	name := ""
+	if c.acceptKeyword("constraint") {
+		name = c.next().text
+	}
+
+	switch {
+	case c.acceptKeyword("primary", "key"):
+		cols, err := c.nameList()
+		if err != nil {
+			return err
+		}
+		for _, name := range cols {
+			if col := findColumn(table, name); col != nil {
+				col.PrimaryKey = true
+				col.NotNull = true
+			} else {
+				p.result.warnf("line %d: primary key on unknown column %s.%s ignored", line, table.Name, name)
+			}
+		}
+
+	case c.acceptKeyword("unique"):
+		c.acceptKeyword("nulls", "not", "distinct")
+		c.acceptKeyword("nulls", "distinct")
+		cols, err := c.nameList()
+		if err != nil {
+			return err
+		}
+		if len(cols) == 1 && name == "" && findColumn(table, cols[0]) != nil {
+			findColumn(table, cols[0]).Unique = true
+		} else {
+			table.Uniques = append(table.Uniques, model.Unique{Name: name, Columns: cols})
+		}
+
+	case c.acceptKeyword("foreign", "key"):
+		cols, err := c.nameList()
+		if err != nil {
+			return err
+		}
+		if !c.acceptKeyword("references") {
+			return fmt.Errorf("line %d: expected REFERENCES in foreign key on %s", line, table.Name)
+		}
+		fk, err := parseReferences(c, name, cols)
+		if err != nil {
+			return err
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+
+	case c.acceptKeyword("check"):
+		check, err := parseCheck(c, name, postgresDialect)
+		if err != nil {
+			return err
+		}
+		table.Checks = append(table.Checks, check)
+
+	default:
+		p.result.warnf("line %d: table constraint on %s ignored", line, table.Name)
+	}
+	return nil
+}
+
+func (p *pgParser) parseAlterTable(c *sqlCursor) error {
+	line := c.peek().line
+	c.acceptKeyword("if", "exists")
+	c.acceptKeyword("only")
+
+	name, err := c.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table := findTable(&p.result.Data, name)
+	if table == nil {
+		p.result.warnf("line %d: ALTER TABLE on unknown table %s ignored", line, name)
+		return nil
+	}
+
+	for _, action := range splitTopLevel(c.tokens[c.pos:]) {
+		ac := &sqlCursor{tokens: action}
+		actionLine := ac.peek().line
+
+		switch {
+		case ac.isKeyword("owner", "to"), ac.isKeyword("replica", "identity"),
+			ac.isKeyword("enable"), ac.isKeyword("disable"), ac.isKeyword("cluster", "on"),
+			ac.isKeyword("set", "without"), ac.isKeyword("attach", "partition"):
+			// Ownership and storage details are not part of the design
+
+		case ac.isKeyword("add") && atTableConstraint(ac, 1):
+			ac.next()
+			if err := p.parseTableConstraint(ac, table); err != nil {
+				return err
+			}
+
+		case ac.isKeyword("add"):
+			ac.next()
+			ac.acceptKeyword("column")
+			ac.acceptKeyword("if", "not", "exists")
+			if err := p.parseColumn(ac, table); err != nil {
+				return err
+			}
+
+		case ac.acceptKeyword("alter"):
+			ac.acceptKeyword("column")
+			colName := ac.next().text
+			col := findColumn(table, colName)
+			if col == nil {
+				p.result.warnf("line %d: ALTER COLUMN on unknown column %s.%s ignored", actionLine, name, colName)
+				continue
+			}
+			switch {
+			case ac.acceptKeyword("set", "default"):
+				p.applyDefault(col, ac.tokens[ac.pos:])
+			case ac.acceptKeyword("drop", "default"):
+				col.Default = nil
+			case ac.acceptKeyword("set", "not", "null"):
+				col.NotNull = true
+			case ac.acceptKeyword("drop", "not", "null"):
+				col.NotNull = false
+			case ac.acceptKeyword("add", "generated"):
+				col.AutoIncrement = true
+				col.NotNull = true
+			default:
+				p.result.warnf("line %d: ALTER COLUMN %s.%s action ignored", actionLine, name, colName)
+			}
+
+		default:
+			p.result.warnf("line %d: ALTER TABLE %s action ignored", actionLine, name)
+		}
+	}
+	return nil
+}
+
+// resolve maps enum type names onto ENUM columns and fills in foreign keys
+// that reference a primary key implicitly
+func (p *pgParser) resolve() {
+	data := &p.result.Data
+	for i := range data.Tables {
+		table := &data.Tables[i]
+		for j := range table.Columns {
+			col := &table.Columns[j]
+			if values, ok := p.enums[strings.ToLower(col.Type)]; ok {
+				col.Type = "ENUM"
+				col.EnumValues = values
+			}
+		}
+
+	}
+
+	resolveImplicitReferences(p.result)
+}
+
+// normalizePostgresType turns the words of a Postgres type into the type
+// names used by the designer; serial types become integers with AutoIncrement
+func normalizePostgresType(words []string) (string, bool) {
+	array := false
+	if words[len(words)-1] == "[]" {
+		array = true
+		words = words[:len(words)-1]
+	}
+	lower := strings.ToLower(strings.Join(words, " "))
+
+	var typ string
+	autoIncrement := false
+	switch lower {
+	case "serial", "serial4":
+		typ, autoIncrement = "INTEGER", true
+	case "bigserial", "serial8":
+		typ, autoIncrement = "BIGINT", true
+	case "smallserial", "serial2":
+		typ, autoIncrement = "SMALLINT", true
+	case "int", "int4", "integer":
+		typ = "INTEGER"
+	case "int8":
+		typ = "BIGINT"
+	case "int2":
+		typ = "SMALLINT"
+	case "character varying", "varchar":
+		typ = "VARCHAR"
+	case "character", "char", "bpchar":
+		typ = "CHAR"
+	case "double precision", "float8":
+		typ = "DOUBLE PRECISION"
+	case "float4":
+		typ = "REAL"
+	case "bool":
+		typ = "BOOLEAN"
+	case "timestamp without time zone":
+		typ = "TIMESTAMP"
+	case "timestamp with time zone":
+		typ = "TIMESTAMPTZ"
+	case "time without time zone", "time with time zone", "timetz":
+		typ = "TIME"
+	default:
+		if len(words) == 1 && !isPostgresBuiltin(lower) {
+			// Probably a user-defined type such as an enum; keep its name for resolve
+			typ = words[0]
+		} else {
+			typ = strings.ToUpper(lower)
+		}
+	}
+
+	if array {
+		typ += "[]"
+	}
+	return typ, autoIncrement
+}
+
+func isPostgresBuiltin(t string) bool {
+	switch t {
+	case "smallint", "bigint", "decimal", "numeric", "real", "float", "money",
+		"text", "bytea", "date", "time", "timestamp", "timestamptz", "interval",
+		"boolean", "json", "jsonb", "uuid", "xml", "inet", "cidr", "macaddr",
+		"bit", "varbit", "tsvector", "tsquery", "point", "line", "box", "circle", "oid":
+		return true
+	}
+	return false
+}
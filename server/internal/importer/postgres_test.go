@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+func TestImportPostgres(t *testing.T) {
+	runImportTests(t, FormatPostgres, []importTest{
+		{
+			name: "pg_dump output",
+			input: `-- pg_dump output
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+CREATE TYPE public.status AS ENUM ('active', 'it''s off');
+CREATE TYPE public.pair AS (a int, b int);
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    email character varying(255) NOT NULL,
+    status public.status DEFAULT 'active'::public.status,
+    balance numeric(10,2) DEFAULT 0,
+    created_at timestamp with time zone DEFAULT now(),
+    CONSTRAINT users_balance_check CHECK ((balance >= (0)::numeric))
+);
+CREATE SEQUENCE public.users_id_seq AS integer START WITH 1;
+ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_email_key UNIQUE (email);
+`,
+			want: []model.Table{{
+				Name: "users",
+				Columns: []model.Column{
+					{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true, AutoIncrement: true},
+					{Name: "email", Type: "VARCHAR", Length: 255, NotNull: true},
+					{Name: "status", Type: "ENUM", Default: ptr("active"), EnumValues: []string{"active", "it's off"}},
+					{Name: "balance", Type: "NUMERIC", Precision: 10, Scale: 2, Default: ptr("0")},
+					{Name: "created_at", Type: "TIMESTAMPTZ", Default: ptr("now()")},
+				},
+				Uniques: []model.Unique{{Name: "users_email_key", Columns: []string{"email"}}},
+				Checks:  []model.Check{{Name: "users_balance_check", Expression: "balance >= (0)::numeric"}},
+			}},
+			warnings: []string{"line 5: type pair ignored, only enum types are supported"},
+		},
+		{
+			name: "inline constraints, indexes and comments",
+			input: `CREATE TABLE users (id serial PRIMARY KEY);
+CREATE TABLE posts (
+  id bigserial PRIMARY KEY,
+  author_id integer REFERENCES users(id) ON DELETE CASCADE,
+  "Title" text NOT NULL,
+  tags text[]
+);
+CREATE INDEX posts_title_idx ON posts USING btree ("Title" DESC) WHERE (author_id IS NOT NULL);
+CREATE INDEX posts_lower_idx ON posts (lower("Title"));
+COMMENT ON TABLE posts IS 'Blog posts';
+COMMENT ON COLUMN posts.tags IS 'free form';
+CREATE VIEW v AS SELECT 1;
+`,
+			want: []model.Table{
+				{
+					Name:    "users",
+					Columns: []model.Column{{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true, AutoIncrement: true}},
+				},
+				{
+					Name: "posts",
+					Columns: []model.Column{
+						{Name: "id", Type: "BIGINT", PrimaryKey: true, NotNull: true, AutoIncrement: true},
+						{Name: "author_id", Type: "INTEGER"},
+						{Name: "Title", Type: "TEXT", NotNull: true},
+						{Name: "tags", Type: "TEXT[]", Comment: "free form"},
+					},
+					ForeignKeys: []model.ForeignKey{{
+						Column:     "author_id",
+						References: model.Reference{Table: "users", Column: "id"},
+						OnDelete:   "CASCADE",
+					}},
+					Indexes: []model.Index{{
+						Name:    "posts_title_idx",
+						Columns: []model.IndexColumn{{Name: "Title", Order: "DESC"}},
+						Where:   "(author_id is not null)",
+					}},
+					Comment: "Blog posts",
+				},
+			},
+			warnings: []string{
+				"line 9: expression index on table posts ignored",
+				"line 12: unsupported statement CREATE VIEW ignored",
+			},
+		},
+		{
+			name: "composite keys and ALTER TABLE",
+			input: `CREATE TABLE t (a int, b int, PRIMARY KEY (a, b), UNIQUE (a, b));
+CREATE INDEX t_ab ON t (a, b DESC);
+ALTER TABLE t ADD COLUMN c text;
+ALTER TABLE t ADD CONSTRAINT t_c FOREIGN KEY (a, b) REFERENCES t (a, b);
+ALTER TABLE missing ADD COLUMN d int;
+`,
+			want: []model.Table{{
+				Name: "t",
+				Columns: []model.Column{
+					{Name: "a", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+					{Name: "b", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+					{Name: "c", Type: "TEXT"},
+				},
+				ForeignKeys: []model.ForeignKey{{
+					Name:       "t_c",
+					Columns:    []string{"a", "b"},
+					References: model.Reference{Table: "t", Columns: []string{"a", "b"}},
+				}},
+				Indexes: []model.Index{{Name: "t_ab", Columns: []model.IndexColumn{{Name: "a"}, {Name: "b", Order: "DESC"}}}},
+				Uniques: []model.Unique{{Columns: []string{"a", "b"}}},
+			}},
+			warnings: []string{"line 5: ALTER TABLE on unknown table missing ignored"},
+		},
+	})
+}
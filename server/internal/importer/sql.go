@@ -0,0 +1,702 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+type sqlTokenKind int
+
+const (
+	sqlEOF sqlTokenKind = iota
+	sqlIdent
+	sqlQuotedIdent
+	sqlString
+	sqlNumber
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	line int
+}
+
+// sqlDialect captures the lexical differences between the supported databases
+type sqlDialect struct {
+	identQuote       rune
+	hashComments     bool
+	backslashEscapes bool
+	dollarQuotes     bool
+	// foldLower lowercases unquoted identifiers, as Postgres does
+	foldLower bool
+}
+
+var postgresDialect = sqlDialect{identQuote: '"', dollarQuotes: true, foldLower: true}
+
+func lexSQL(input string, d sqlDialect) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(input)
+	line := 1
+
+	at := func(i int, s string) bool {
+		for j, r := range s {
+			if i+j >= len(runes) || runes[i+j] != r {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case r == '\n':
+			line++
+			i++
+
+		case unicode.IsSpace(r):
+			i++
+
+		case at(i, "--") || (d.hashComments && r == '#'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case at(i, "/*"):
+			// MySQL's /*!40101 ... */ version comments are treated as plain comments
+			start := line
+			i += 2
+			for i < len(runes) && !at(i, "*/") {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+
+		case d.dollarQuotes && r == '$' && i+1 < len(runes) && (runes[i+1] == '$' || unicode.IsLetter(runes[i+1]) || runes[i+1] == '_'):
+			// $tag$ ... $tag$ bodies, used by pg_dump for functions
+			end := i + 1
+			for end < len(runes) && runes[end] != '$' && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			if end >= len(runes) || runes[end] != '$' {
+				tokens = append(tokens, sqlToken{kind: sqlPunct, text: "$", line: line})
+				i++
+				continue
+			}
+			tag := string(runes[i : end+1])
+			start := line
+			i = end + 1
+			var sb strings.Builder
+			for i < len(runes) && !at(i, tag) {
+				if runes[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated dollar-quoted string", start)
+			}
+			i += len([]rune(tag))
+			tokens = append(tokens, sqlToken{kind: sqlString, text: sb.String(), line: start})
+
+		case r == '\'' || r == d.identQuote || (d.identQuote == '`' && r == '"'):
+			start := line
+			quote := r
+			kind := sqlString
+			if quote == d.identQuote {
+				kind = sqlQuotedIdent
+			}
+			// Postgres E'...' strings honour backslash escapes
+			escapes := d.backslashEscapes && kind == sqlString
+			if kind == sqlString && len(tokens) > 0 && !d.backslashEscapes {
+				prev := tokens[len(tokens)-1]
+				if prev.kind == sqlIdent && strings.EqualFold(prev.text, "E") && prev.line == line {
+					tokens = tokens[:len(tokens)-1]
+					escapes = true
+				}
+			}
+
+			i++
+			var sb strings.Builder
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+				}
+				c := runes[i]
+				if c == quote {
+					// A doubled quote is an escaped quote
+					if i+1 < len(runes) && runes[i+1] == quote {
+						sb.WriteRune(quote)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				if escapes && c == '\\' && i+1 < len(runes) {
+					i++
+					sb.WriteRune(unescapeSQL(runes[i]))
+					i++
+					continue
+				}
+				if c == '\n' {
+					line++
+				}
+				sb.WriteRune(c)
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: sb.String(), line: start})
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: string(runes[start:i]), line: line})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			text := string(runes[start:i])
+			if d.foldLower {
+				text = strings.ToLower(text)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: text, line: line})
+
+		case at(i, "::") || at(i, "<=") || at(i, ">=") || at(i, "<>") || at(i, "!=") || at(i, "||"):
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(runes[i : i+2]), line: line})
+			i += 2
+
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(r), line: line})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+func unescapeSQL(r rune) rune {
+	switch r {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	default:
+		return r
+	}
+}
+
+// splitStatements groups tokens into statements separated by semicolons
+func splitStatements(tokens []sqlToken) [][]sqlToken {
+	var stmts [][]sqlToken
+	var current []sqlToken
+	for _, t := range tokens {
+		if t.kind == sqlPunct && t.text == ";" {
+			if len(current) > 0 {
+				stmts = append(stmts, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, t)
+	}
+	if len(current) > 0 {
+		stmts = append(stmts, current)
+	}
+	return stmts
+}
+
+// sqlCursor walks the tokens of a single statement
+type sqlCursor struct {
+	tokens []sqlToken
+	pos    int
+}
+
+func (c *sqlCursor) done() bool {
+	return c.pos >= len(c.tokens)
+}
+
+func (c *sqlCursor) peek() sqlToken {
+	return c.peekAt(0)
+}
+
+func (c *sqlCursor) peekAt(offset int) sqlToken {
+	if c.pos+offset >= len(c.tokens) {
+		line := 0
+		if len(c.tokens) > 0 {
+			line = c.tokens[len(c.tokens)-1].line
+		}
+		return sqlToken{kind: sqlEOF, line: line}
+	}
+	return c.tokens[c.pos+offset]
+}
+
+func (c *sqlCursor) next() sqlToken {
+	t := c.peek()
+	if !c.done() {
+		c.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether the upcoming tokens are the given unquoted words
+func (c *sqlCursor) isKeyword(words ...string) bool {
+	for i, w := range words {
+		t := c.peekAt(i)
+		if t.kind != sqlIdent || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeyword consumes the given words if they come next
+func (c *sqlCursor) acceptKeyword(words ...string) bool {
+	if !c.isKeyword(words...) {
+		return false
+	}
+	c.pos += len(words)
+	return true
+}
+
+func (c *sqlCursor) isPunct(p string) bool {
+	t := c.peek()
+	return t.kind == sqlPunct && t.text == p
+}
+
+func (c *sqlCursor) acceptPunct(p string) bool {
+	if !c.isPunct(p) {
+		return false
+	}
+	c.pos++
+	return true
+}
+
+func (c *sqlCursor) expectPunct(p string) error {
+	t := c.next()
+	if t.kind != sqlPunct || t.text != p {
+		return fmt.Errorf("line %d: expected %q, got %q", t.line, p, t.text)
+	}
+	return nil
+}
+
+// parenthesized consumes a balanced ( ... ) group and returns the tokens inside
+func (c *sqlCursor) parenthesized() ([]sqlToken, error) {
+	start := c.peek().line
+	if err := c.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var inner []sqlToken
+	for depth := 1; ; {
+		if c.done() {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", start)
+		}
+		t := c.next()
+		if t.kind == sqlPunct && t.text == "(" {
+			depth++
+		} else if t.kind == sqlPunct && t.text == ")" {
+			depth--
+			if depth == 0 {
+				return inner, nil
+			}
+		}
+		inner = append(inner, t)
+	}
+}
+
+// nameList parses ( a, b, c ), ignoring per-column extras such as ASC or a
+// MySQL prefix length
+func (c *sqlCursor) nameList() ([]string, error) {
+	inner, err := c.parenthesized()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, group := range splitTopLevel(inner) {
+		if len(group) > 0 && (group[0].kind == sqlIdent || group[0].kind == sqlQuotedIdent) {
+			names = append(names, group[0].text)
+		}
+	}
+	return names, nil
+}
+
+// indexColumnList reads an index's ( ... ) column list along with each
+// column's sort order. MySQL prefix lengths are dropped; ok is false when an
+// entry is an expression rather than a plain column.
+func (c *sqlCursor) indexColumnList() (cols []model.IndexColumn, ok bool, err error) {
+	inner, err := c.parenthesized()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, group := range splitTopLevel(inner) {
+		if len(group) == 0 || (group[0].kind != sqlIdent && group[0].kind != sqlQuotedIdent) {
+			return nil, false, nil
+		}
+		col := model.IndexColumn{Name: group[0].text}
+		rest := group[1:]
+		if len(rest) >= 3 && rest[0].text == "(" && rest[1].kind == sqlNumber && rest[2].text == ")" {
+			rest = rest[3:]
+		}
+		for _, t := range rest {
+			switch {
+			case t.kind == sqlPunct:
+				return nil, false, nil
+			case t.kind == sqlIdent && strings.EqualFold(t.text, "asc"):
+				col.Order = "ASC"
+			case t.kind == sqlIdent && strings.EqualFold(t.text, "desc"):
+				col.Order = "DESC"
+			}
+		}
+		cols = append(cols, col)
+	}
+	return cols, true, nil
+}
+
+// qualifiedName reads name or schema.name; the schema is dropped since the
+// designer has no notion of schemas
+func (c *sqlCursor) qualifiedName() (string, error) {
+	t := c.next()
+	if t.kind != sqlIdent && t.kind != sqlQuotedIdent {
+		return "", fmt.Errorf("line %d: expected name, got %q", t.line, t.text)
+	}
+	name := t.text
+	for c.isPunct(".") && (c.peekAt(1).kind == sqlIdent || c.peekAt(1).kind == sqlQuotedIdent) {
+		c.next()
+		name = c.next().text
+	}
+	return name, nil
+}
+
+// skipUntilTopLevel advances to the next "," or ")" outside parentheses, or
+// to the end of the statement
+func (c *sqlCursor) skipUntilTopLevel() {
+	depth := 0
+	for !c.done() {
+		t := c.peek()
+		if t.kind == sqlPunct {
+			switch t.text {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					return
+				}
+				depth--
+			case ",":
+				if depth == 0 {
+					return
+				}
+			}
+		}
+		c.next()
+	}
+}
+
+// skipExpression advances past an expression such as a DEFAULT value, up to
+// the next word in stop outside parentheses. The first token is always
+// consumed, so DEFAULT NULL keeps its NULL.
+func (c *sqlCursor) skipExpression(stop map[string]bool) {
+	depth := 0
+	for first := true; !c.done(); first = false {
+		t := c.peek()
+		switch {
+		case t.kind == sqlPunct && t.text == "(":
+			depth++
+		case t.kind == sqlPunct && t.text == ")":
+			depth--
+		case depth == 0 && !first && t.kind == sqlIdent && stop[strings.ToLower(t.text)]:
+			return
+		}
+		c.next()
+	}
+}
+
+// splitTopLevel splits tokens on commas that are not nested in parentheses
+func splitTopLevel(tokens []sqlToken) [][]sqlToken {
+	var groups [][]sqlToken
+	var current []sqlToken
+	depth := 0
+	for _, t := range tokens {
+		if t.kind == sqlPunct {
+			switch t.text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			case ",":
+				if depth == 0 {
+					groups = append(groups, current)
+					current = nil
+					continue
+				}
+			}
+		}
+		current = append(current, t)
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// numericArgs returns the integers of a type argument list such as (10, 2),
+// or nil when the list holds anything else
+func numericArgs(tokens []sqlToken) []int {
+	var args []int
+	for _, part := range splitTopLevel(tokens) {
+		if len(part) != 1 || part[0].kind != sqlNumber {
+			return nil
+		}
+		n, err := strconv.Atoi(part[0].text)
+		if err != nil {
+			return nil
+		}
+		args = append(args, n)
+	}
+	return args
+}
+
+// parseCheck reads the parenthesized expression of a CHECK constraint.
+// Quoting is dropped from plain lowercase identifiers so that the expression
+// can be exported to any dialect.
+func parseCheck(c *sqlCursor, name string, d sqlDialect) (model.Check, error) {
+	inner, err := c.parenthesized()
+	if err != nil {
+		return model.Check{}, err
+	}
+	// Dumps wrap the whole expression in another pair of parentheses
+	for isParenthesized(inner) {
+		inner = inner[1 : len(inner)-1]
+	}
+
+	tokens := make([]sqlToken, len(inner))
+	for i, t := range inner {
+		if t.kind == sqlQuotedIdent && plainIdentRe.MatchString(t.text) {
+			t.kind = sqlIdent
+		}
+		tokens[i] = t
+	}
+	return model.Check{Name: name, Expression: renderSQL(tokens, d)}, nil
+}
+
+var plainIdentRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// isParenthesized reports whether the tokens are a single ( ... ) group
+func isParenthesized(tokens []sqlToken) bool {
+	if len(tokens) < 2 || tokens[0].kind != sqlPunct || tokens[0].text != "(" {
+		return false
+	}
+	depth := 0
+	for i, t := range tokens {
+		if t.kind != sqlPunct {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i == len(tokens)-1
+			}
+		}
+	}
+	return false
+}
+
+// renderSQL turns tokens back into source text, e.g. for default expressions
+func renderSQL(tokens []sqlToken, d sqlDialect) string {
+	var sb strings.Builder
+	for i, t := range tokens {
+		text := t.text
+		switch t.kind {
+		case sqlString:
+			text = "'" + strings.ReplaceAll(t.text, "'", "''") + "'"
+		case sqlQuotedIdent:
+			q := string(d.identQuote)
+			text = q + strings.ReplaceAll(t.text, q, q+q) + q
+		}
+
+		if i > 0 {
+			prev := tokens[i-1]
+			noSpace := (prev.kind == sqlPunct && (prev.text == "(" || prev.text == "." || prev.text == "::")) ||
+				(t.kind == sqlPunct && (t.text == ")" || t.text == "," || t.text == "." || t.text == "::")) ||
+				(t.kind == sqlPunct && t.text == "(" && (prev.kind == sqlIdent || prev.kind == sqlQuotedIdent))
+			if !noSpace {
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// literalDefault converts a DEFAULT expression into the value stored in
+// model.Column: plain literals lose their quoting and casts, anything else is
+// kept as an expression
+func literalDefault(tokens []sqlToken, d sqlDialect) string {
+	// Drop trailing casts such as 'x'::character varying
+	for i, t := range tokens {
+		if t.kind == sqlPunct && t.text == "::" {
+			tokens = tokens[:i]
+			break
+		}
+	}
+	for len(tokens) >= 2 && tokens[0].kind == sqlPunct && tokens[0].text == "(" &&
+		tokens[len(tokens)-1].kind == sqlPunct && tokens[len(tokens)-1].text == ")" {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	switch {
+	case len(tokens) == 1 && tokens[0].kind == sqlString:
+		return tokens[0].text
+	case len(tokens) == 1 && tokens[0].kind == sqlNumber:
+		return tokens[0].text
+	case len(tokens) == 2 && tokens[0].kind == sqlPunct && tokens[0].text == "-" && tokens[1].kind == sqlNumber:
+		return "-" + tokens[1].text
+	case len(tokens) == 1 && tokens[0].kind == sqlIdent:
+		return strings.ToUpper(tokens[0].text)
+	}
+	return renderSQL(tokens, d)
+}
+
+// parseReferences reads the part of a foreign key after REFERENCES; the
+// referenced columns may be omitted, in which case they are resolved to the
+// target's primary key once all tables are known
+func parseReferences(c *sqlCursor, name string, columns []string) (model.ForeignKey, error) {
+	refTable, err := c.qualifiedName()
+	if err != nil {
+		return model.ForeignKey{}, err
+	}
+
+	var refColumns []string
+	if c.isPunct("(") {
+		if refColumns, err = c.nameList(); err != nil {
+			return model.ForeignKey{}, err
+		}
+	}
+
+	fk := newForeignKey(columns, refTable, refColumns)
+	fk.Name = name
+options:
+	for !c.done() {
+		switch {
+		case c.acceptKeyword("on", "delete"):
+			fk.OnDelete = normalizeAction(c)
+		case c.acceptKeyword("on", "update"):
+			fk.OnUpdate = normalizeAction(c)
+		case c.acceptKeyword("match"):
+			fk.Match = strings.ToUpper(c.next().text)
+		case c.acceptKeyword("deferrable"):
+			fk.Deferrable = true
+		case c.acceptKeyword("not", "deferrable"):
+			fk.Deferrable = false
+		case c.acceptKeyword("initially", "deferred"):
+			// INITIALLY DEFERRED implies DEFERRABLE
+			fk.Deferrable = true
+			fk.InitiallyDeferred = true
+		case c.acceptKeyword("initially", "immediate"):
+			fk.InitiallyDeferred = false
+		default:
+			break options
+		}
+	}
+
+	return fk, nil
+}
+
+// newForeignKey stores a key in the single-column form when it has one column
+// and in the composite form otherwise
+func newForeignKey(columns []string, refTable string, refColumns []string) model.ForeignKey {
+	fk := model.ForeignKey{References: model.Reference{Table: refTable}}
+	if len(columns) == 1 && len(refColumns) <= 1 {
+		fk.Column = columns[0]
+		if len(refColumns) == 1 {
+			fk.References.Column = refColumns[0]
+		}
+		return fk
+	}
+	fk.Columns = columns
+	fk.References.Columns = refColumns
+	return fk
+}
+
+// resolveImplicitReferences points foreign keys declared without referenced
+// columns at the primary key of their target table
+func resolveImplicitReferences(result *Result) {
+	data := &result.Data
+	for i := range data.Tables {
+		table := &data.Tables[i]
+		for j := range table.ForeignKeys {
+			fk := &table.ForeignKeys[j]
+			if len(fk.TargetColumns()) > 0 {
+				continue
+			}
+
+			var pk []string
+			if target := findTable(data, fk.References.Table); target != nil {
+				for _, col := range target.Columns {
+					if col.PrimaryKey {
+						pk = append(pk, col.Name)
+					}
+				}
+			}
+
+			source := fk.SourceColumns()
+			switch {
+			case len(pk) == 0 || len(pk) != len(source):
+				result.warnf("foreign key %s.%s references %s without matching columns", table.Name, strings.Join(source, ","), fk.References.Table)
+			case fk.IsComposite():
+				fk.References.Columns = pk
+			default:
+				fk.References.Column = pk[0]
+			}
+		}
+	}
+}
+
+// normalizeAction upper-cases referential actions, e.g. "set null" -> "SET NULL"
+func normalizeAction(c *sqlCursor) string {
+	switch {
+	case c.acceptKeyword("cascade"):
+		return "CASCADE"
+	case c.acceptKeyword("restrict"):
+		return "RESTRICT"
+	case c.acceptKeyword("set", "null"):
+		return "SET NULL"
+	case c.acceptKeyword("set", "default"):
+		return "SET DEFAULT"
+	case c.acceptKeyword("no", "action"):
+		return "NO ACTION"
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/logger"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// APITokenPrefix starts every API token, which tells them apart from the
+// JWT access tokens of signed in sessions
+const APITokenPrefix = "dsg_"
+
+const ScopesKey contextKey = "scopes"
+
+// APITokenFinder looks up API tokens by the SHA-256 hash they are stored as
+type APITokenFinder interface {
+	FindByHash(hash string) (*model.APIToken, error)
+	Touch(id int) error
+}
+
+// APITokenAuth accepts bearer API tokens and records their scopes in the
+// request context. Requests with any other authorization are passed on
+// untouched, for JWTAuth to handle.
+func APITokenAuth(tokens APITokenFinder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "+APITokenPrefix)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			sum := sha256.Sum256([]byte(APITokenPrefix + raw))
+			token, err := tokens.FindByHash(hex.EncodeToString(sum[:]))
+			if err != nil {
+				http.Error(w, `{"error":"failed to fetch api token"}`, http.StatusInternalServerError)
+				return
+			}
+			if token == nil {
+				http.Error(w, `{"error":"invalid api token"}`, http.StatusUnauthorized)
+				return
+			}
+			if token.Expired() {
+				http.Error(w, `{"error":"api token expired"}`, http.StatusUnauthorized)
+				return
+			}
+
+			if err := tokens.Touch(token.ID); err != nil {
+				logger.Warn.Printf("recording use of api token %d: %v", token.ID, err)
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, token.UserID)
+			ctx = context.WithValue(ctx, ScopesKey, token.Scopes)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope rejects API tokens that were not granted scope. Signed in
+// sessions have every scope.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if scopes, ok := GetScopes(r.Context()); ok && !scopes.Has(scope) {
+				http.Error(w, `{"error":"api token lacks the `+scope+` scope"}`, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects API tokens, keeping account management to signed
+// in sessions
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetSessionID(r.Context()); !ok {
+			http.Error(w, `{"error":"not available to api tokens"}`, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetScopes returns the scopes of the API token the request was made with.
+// It reports false for signed in sessions.
+func GetScopes(ctx context.Context) (model.Scopes, bool) {
+	scopes, ok := ctx.Value(ScopesKey).(model.Scopes)
+	return scopes, ok
+}
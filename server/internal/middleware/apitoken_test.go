@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "secret"
+
+type fakeTokens map[string]*model.APIToken
+
+func (f fakeTokens) FindByHash(hash string) (*model.APIToken, error) { return f[hash], nil }
+func (f fakeTokens) Touch(id int) error                              { return nil }
+
+type fakeSessions map[int]*model.Session
+
+func (f fakeSessions) FindByID(id int) (*model.Session, error) { return f[id], nil }
+
+func addToken(tokens fakeTokens, raw string, token *model.APIToken) {
+	sum := sha256.Sum256([]byte(raw))
+	tokens[hex.EncodeToString(sum[:])] = token
+}
+
+func accessToken(t *testing.T, userID, sessionID int, expires time.Time) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"sid":     sessionID,
+		"exp":     expires.Unix(),
+	})
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestAuthChain(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tokens := fakeTokens{}
+	addToken(tokens, "dsg_read", &model.APIToken{ID: 1, UserID: 1, Scopes: model.Scopes{model.ScopeSchemasRead}})
+	addToken(tokens, "dsg_export", &model.APIToken{ID: 2, UserID: 1, Scopes: model.Scopes{model.ScopeExport}})
+	addToken(tokens, "dsg_expired", &model.APIToken{ID: 3, UserID: 1, Scopes: model.Scopes{model.ScopeExport}, ExpiresAt: &past})
+	sessions := fakeSessions{
+		1: {ID: 1, UserID: 1},
+		2: {ID: 2, UserID: 1, RevokedAt: &past},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	chain := func(h http.Handler) http.Handler {
+		return APITokenAuth(tokens)(JWTAuth(testSecret, sessions)(h))
+	}
+	export := chain(RequireScope(model.ScopeExport)(ok))
+	account := chain(RequireSession(ok))
+
+	for _, tt := range []struct {
+		name    string
+		handler http.Handler
+		auth    string
+		want    int
+	}{
+		{"scoped token", export, "Bearer dsg_export", http.StatusOK},
+		{"wrong scope", export, "Bearer dsg_read", http.StatusForbidden},
+		{"expired api token", export, "Bearer dsg_expired", http.StatusUnauthorized},
+		{"unknown api token", export, "Bearer dsg_unknown", http.StatusUnauthorized},
+		{"session has every scope", export, "Bearer " + accessToken(t, 1, 1, time.Now().Add(time.Minute)), http.StatusOK},
+		{"expired access token", export, "Bearer " + accessToken(t, 1, 1, past), http.StatusUnauthorized},
+		{"revoked session", export, "Bearer " + accessToken(t, 1, 2, time.Now().Add(time.Minute)), http.StatusUnauthorized},
+		{"missing authorization", export, "", http.StatusUnauthorized},
+		{"api token on account route", account, "Bearer dsg_export", http.StatusForbidden},
+		{"session on account route", account, "Bearer " + accessToken(t, 1, 1, time.Now().Add(time.Minute)), http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		w := httptest.NewRecorder()
+		tt.handler.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, w.Code, tt.want, w.Body)
+		}
+	}
+}
@@ -5,16 +5,33 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Dragodui/db-schemas-generator/internal/model"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey    contextKey = "userID"
+	SessionIDKey contextKey = "sessionID"
+)
+
+// SessionFinder looks up the session an access token was issued for
+type SessionFinder interface {
+	FindByID(id int) (*model.Session, error)
+}
 
-func JWTAuth(jwtSecret string) func(http.Handler) http.Handler {
+// JWTAuth accepts bearer access tokens signed with jwtSecret whose session
+// has not been revoked. Requests that APITokenAuth already authenticated are
+// let through.
+func JWTAuth(jwtSecret string, sessions SessionFinder) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetScopes(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
 				http.Error(w, `{"error":"missing authorization header"}`, http.StatusUnauthorized)
@@ -52,7 +69,24 @@ func JWTAuth(jwtSecret string) func(http.Handler) http.Handler {
 				return
 			}
 
+			sessionID, ok := claims["sid"].(float64)
+			if !ok {
+				http.Error(w, `{"error":"invalid sid in token"}`, http.StatusUnauthorized)
+				return
+			}
+
+			session, err := sessions.FindByID(int(sessionID))
+			if err != nil {
+				http.Error(w, `{"error":"failed to fetch session"}`, http.StatusInternalServerError)
+				return
+			}
+			if session == nil || session.RevokedAt != nil || session.UserID != int(userID) {
+				http.Error(w, `{"error":"session revoked"}`, http.StatusUnauthorized)
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), UserIDKey, int(userID))
+			ctx = context.WithValue(ctx, SessionIDKey, session.ID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -62,3 +96,8 @@ func GetUserID(ctx context.Context) (int, bool) {
 	userID, ok := ctx.Value(UserIDKey).(int)
 	return userID, ok
 }
+
+func GetSessionID(ctx context.Context) (int, bool) {
+	sessionID, ok := ctx.Value(SessionIDKey).(int)
+	return sessionID, ok
+}
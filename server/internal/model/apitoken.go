@@ -0,0 +1,61 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Scopes an API token can be granted
+const (
+	ScopeSchemasRead  = "schemas:read"
+	ScopeSchemasWrite = "schemas:write"
+	ScopeExport       = "export"
+)
+
+// APIScopes lists every scope, in the order they are documented
+var APIScopes = []string{ScopeSchemasRead, ScopeSchemasWrite, ScopeExport}
+
+// APIToken is a personal access token for scripts and CI. Only the hash of
+// the token is stored; Prefix keeps enough of it to tell tokens apart.
+type APIToken struct {
+	ID         int        `gorm:"autoIncrement;primaryKey" json:"id"`
+	UserID     int        `gorm:"not null;index" json:"-"`
+	Name       string     `gorm:"size:64;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     Scopes     `gorm:"type:jsonb;not null" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Expired reports whether the token is past its expiry date
+func (t *APIToken) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+type Scopes []string
+
+// Has reports whether scope is among the granted scopes
+func (s Scopes) Has(scope string) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Scopes) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *Scopes) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Identity links a user to their account at a single sign-on provider.
+// Subject is the provider's stable ID for the account; the email address
+// there may change.
+type Identity struct {
+	ID        int       `gorm:"autoIncrement;primaryKey" json:"id"`
+	UserID    int       `gorm:"not null;index" json:"-"`
+	Provider  string    `gorm:"size:64;not null;uniqueIndex:idx_identity_subject" json:"provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"-"`
+	Email     string    `gorm:"size:255" json:"email"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
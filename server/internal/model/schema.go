@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -25,30 +26,122 @@ type Table struct {
 	Name        string       `json:"name"`
 	Columns     []Column     `json:"columns"`
 	ForeignKeys []ForeignKey `json:"foreignKeys,omitempty"`
+	Indexes     []Index      `json:"indexes,omitempty"`
+	Uniques     []Unique     `json:"uniques,omitempty"`
+	Checks      []Check      `json:"checks,omitempty"`
 	Engine      string       `json:"engine,omitempty"`
+	Comment     string       `json:"comment,omitempty"`
 }
 
 type Column struct {
 	Name          string   `json:"name"`
 	Type          string   `json:"type"`
+	Length        int      `json:"length,omitempty"`
+	Precision     int      `json:"precision,omitempty"`
+	Scale         int      `json:"scale,omitempty"`
+	Unsigned      bool     `json:"unsigned,omitempty"`
 	PrimaryKey    bool     `json:"primaryKey,omitempty"`
 	NotNull       bool     `json:"notNull,omitempty"`
 	Unique        bool     `json:"unique,omitempty"`
 	Default       *string  `json:"default,omitempty"`
 	AutoIncrement bool     `json:"autoIncrement,omitempty"`
 	EnumValues    []string `json:"enumValues,omitempty"`
+	Comment       string   `json:"comment,omitempty"`
 }
 
+// ForeignKey references another table. Single-column keys use Column and
+// References.Column; composite keys list their columns pairwise in Columns
+// and References.Columns instead.
 type ForeignKey struct {
-	Column     string    `json:"column"`
+	Name       string    `json:"name,omitempty"`
+	Column     string    `json:"column,omitempty"`
+	Columns    []string  `json:"columns,omitempty"`
 	References Reference `json:"references"`
 	OnDelete   string    `json:"onDelete,omitempty"`
 	OnUpdate   string    `json:"onUpdate,omitempty"`
+	// Match is the MATCH option: FULL, PARTIAL or SIMPLE
+	Match             string `json:"match,omitempty"`
+	Deferrable        bool   `json:"deferrable,omitempty"`
+	InitiallyDeferred bool   `json:"initiallyDeferred,omitempty"`
 }
 
 type Reference struct {
-	Table  string `json:"table"`
-	Column string `json:"column"`
+	Table   string   `json:"table"`
+	Column  string   `json:"column,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+// SourceColumns returns the referencing columns in order
+func (fk ForeignKey) SourceColumns() []string {
+	if len(fk.Columns) > 0 {
+		return fk.Columns
+	}
+	if fk.Column != "" {
+		return []string{fk.Column}
+	}
+	return nil
+}
+
+// TargetColumns returns the referenced columns in order
+func (fk ForeignKey) TargetColumns() []string {
+	if len(fk.References.Columns) > 0 {
+		return fk.References.Columns
+	}
+	if fk.References.Column != "" {
+		return []string{fk.References.Column}
+	}
+	return nil
+}
+
+// IsComposite reports whether the key spans more than one column
+func (fk ForeignKey) IsComposite() bool {
+	return len(fk.SourceColumns()) > 1
+}
+
+// Unique is a table-level UNIQUE constraint over one or more columns
+type Unique struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+}
+
+// Check is a CHECK constraint. Expression is a boolean SQL expression such as
+// "price >= 0", written without the surrounding parentheses.
+type Check struct {
+	Name       string `json:"name,omitempty"`
+	Expression string `json:"expression"`
+}
+
+// Index is a secondary index on a table. Method, Where and Include are
+// applied only by the formats that support them.
+type Index struct {
+	Name    string        `json:"name,omitempty"`
+	Columns []IndexColumn `json:"columns"`
+	Unique  bool          `json:"unique,omitempty"`
+	// Method is the index access method, such as btree, hash, gin or gist
+	Method string `json:"method,omitempty"`
+	// Where is the predicate of a partial index, written in SQL
+	Where   string   `json:"where,omitempty"`
+	Include []string `json:"include,omitempty"`
+}
+
+type IndexColumn struct {
+	Name string `json:"name"`
+	// Order is ASC or DESC; empty means the database default
+	Order string `json:"order,omitempty"`
+}
+
+// ColumnNames returns the indexed columns in order
+func (idx Index) ColumnNames() []string {
+	names := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// IsDescending reports whether the column is sorted in descending order
+func (c IndexColumn) IsDescending() bool {
+	return strings.EqualFold(c.Order, "DESC")
 }
 
 func (s SchemaData) Value() (driver.Value, error) {
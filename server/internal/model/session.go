@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Session is a signed in device. Its refresh token is rotated on every use;
+// Family identifies the session inside the token, so that an old token
+// coming back can be recognized as stolen.
+type Session struct {
+	ID          int        `gorm:"autoIncrement;primaryKey" json:"id"`
+	UserID      int        `gorm:"not null;index" json:"-"`
+	Family      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	RefreshHash string     `gorm:"size:64;not null" json:"-"`
+	UserAgent   string     `gorm:"size:255" json:"user_agent"`
+	IP          string     `gorm:"size:64" json:"ip"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastUsedAt  time.Time  `json:"last_used_at"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt   *time.Time `json:"-"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// SchemaVersion is an immutable snapshot of a schema's name and data, taken
+// each time the schema is created, changed or restored
+type SchemaVersion struct {
+	ID        int        `gorm:"autoIncrement;primaryKey" json:"id"`
+	SchemaID  int        `gorm:"not null;uniqueIndex:idx_schema_versions_schema_version" json:"schema_id"`
+	Version   int        `gorm:"not null;uniqueIndex:idx_schema_versions_schema_version" json:"version"`
+	AuthorID  int        `gorm:"not null" json:"author_id"`
+	Message   string     `gorm:"size:255" json:"message"`
+	Name      string     `gorm:"size:128;not null" json:"name"`
+	Data      SchemaData `gorm:"type:jsonb;not null" json:"data"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
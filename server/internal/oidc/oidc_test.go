@@ -0,0 +1,121 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/oidc"
+	"github.com/Dragodui/db-schemas-generator/internal/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const redirectURI = "http://api.test/api/auth/oidc/test/callback"
+
+// login runs the code flow against srv and returns the verified claims
+func login(t *testing.T, srv *oidctest.Server, p *oidc.Provider, verifier string) (*oidc.Claims, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, err := p.AuthCodeURL(ctx, redirectURI, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := srv.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Query().Get("state"); got != "state-1" {
+		t.Fatalf("state came back as %q", got)
+	}
+	return p.Exchange(ctx, redirectURI, u.Query().Get("code"), verifier, "nonce-1")
+}
+
+func TestCodeFlow(t *testing.T) {
+	srv := oidctest.NewServer()
+	defer srv.Close()
+	srv.Claims = jwt.MapClaims{"sub": "user-1", "email": "ada@example.com", "email_verified": "true", "name": "Ada"}
+
+	p := oidc.NewProvider(srv.Provider("test"))
+	authURL, err := p.AuthCodeURL(context.Background(), redirectURI, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(authURL, srv.URL+"/authorize?") {
+		t.Errorf("auth URL %s does not use the discovered endpoint", authURL)
+	}
+	q, _ := url.ParseQuery(authURL[strings.Index(authURL, "?")+1:])
+	if q.Get("scope") != "openid email profile" || q.Get("nonce") != "nonce-1" || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("auth URL parameters: %v", q)
+	}
+
+	claims, err := login(t, srv, p, "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := oidc.Claims{Subject: "user-1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+	if *claims != want {
+		t.Errorf("claims = %+v, want %+v", *claims, want)
+	}
+
+	if _, err := login(t, srv, p, "another-verifier"); err == nil {
+		t.Error("code was redeemed with the wrong PKCE verifier")
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	srv := oidctest.NewServer()
+	defer srv.Close()
+
+	cfg := srv.Provider("test")
+	cfg.Issuer += "/"
+	p := oidc.NewProvider(cfg)
+	if _, err := p.AuthCodeURL(context.Background(), redirectURI, "s", "n", "v"); err == nil {
+		t.Error("discovery document for another issuer was accepted")
+	}
+}
+
+func TestIDTokenVerification(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		edit     func(claims jwt.MapClaims)
+		signWith *rsa.PrivateKey
+	}{
+		{name: "bad signature", signWith: otherKey},
+		{name: "wrong issuer", edit: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", edit: func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{name: "wrong authorized party", edit: func(c jwt.MapClaims) {
+			c["aud"] = []string{oidctest.ClientID, "another-client"}
+			c["azp"] = "another-client"
+		}},
+		{name: "wrong nonce", edit: func(c jwt.MapClaims) { c["nonce"] = "replayed" }},
+		{name: "expired", edit: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no expiry", edit: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "no subject", edit: func(c jwt.MapClaims) { delete(c, "sub") }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := oidctest.NewServer()
+			defer srv.Close()
+			srv.Claims = jwt.MapClaims{"sub": "user-1"}
+			srv.Edit = tt.edit
+			srv.SignWith = tt.signWith
+
+			p := oidc.NewProvider(srv.Provider("test"))
+			if claims, err := login(t, srv, p, "verifier-1"); err == nil {
+				t.Errorf("token was accepted: %+v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(t *model.APIToken) error
+	FindByHash(hash string) (*model.APIToken, error)
+	// FindByUserID lists the user's tokens, newest first
+	FindByUserID(userID int) ([]model.APIToken, error)
+	Touch(id int) error
+	Delete(id, userID int) error
+}
+
+type apiTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepo{db: db}
+}
+
+func (r *apiTokenRepo) Create(t *model.APIToken) error {
+	return r.db.Create(t).Error
+}
+
+func (r *apiTokenRepo) FindByHash(hash string) (*model.APIToken, error) {
+	var t model.APIToken
+	err := r.db.Where("token_hash = ?", hash).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &t, err
+}
+
+func (r *apiTokenRepo) FindByUserID(userID int) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Touch records that the token was just used
+func (r *apiTokenRepo) Touch(id int) error {
+	return r.db.Model(&model.APIToken{}).Where("id = ?", id).Update("last_used_at", time.Now()).Error
+}
+
+func (r *apiTokenRepo) Delete(id, userID int) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.APIToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("api token not found")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"gorm.io/gorm"
+)
+
+type IdentityRepository interface {
+	Create(i *model.Identity) error
+	// CreateWithUser creates a new user together with their first identity
+	CreateWithUser(u *model.User, i *model.Identity) error
+	FindBySubject(provider, subject string) (*model.Identity, error)
+}
+
+type identityRepo struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepo{db: db}
+}
+
+func (r *identityRepo) Create(i *model.Identity) error {
+	return r.db.Create(i).Error
+}
+
+func (r *identityRepo) CreateWithUser(u *model.User, i *model.Identity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(u).Error; err != nil {
+			return err
+		}
+		i.UserID = u.ID
+		return tx.Create(i).Error
+	})
+}
+
+func (r *identityRepo) FindBySubject(provider, subject string) (*model.Identity, error) {
+	var i model.Identity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&i).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &i, err
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Dragodui/db-schemas-generator/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SchemaRepository interface {
@@ -13,6 +14,9 @@ type SchemaRepository interface {
 	FindByUserID(userID int) ([]model.Schema, error)
 	FindPublic() ([]model.Schema, error)
 	Update(s *model.Schema) error
+	// UpdateWithVersion saves the schema and records its new name and data
+	// as the next version, filling in v's schema, number and snapshot
+	UpdateWithVersion(s *model.Schema, v *model.SchemaVersion) error
 	Delete(id int, userID int) error
 }
 
@@ -24,8 +28,21 @@ func NewSchemaRepository(db *gorm.DB) SchemaRepository {
 	return &schemaRepo{db: db}
 }
 
+// Create saves a new schema along with its first version
 func (r *schemaRepo) Create(s *model.Schema) error {
-	return r.db.Create(s).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(s).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.SchemaVersion{
+			SchemaID: s.ID,
+			Version:  1,
+			AuthorID: s.UserID,
+			Message:  "Created",
+			Name:     s.Name,
+			Data:     s.Data,
+		}).Error
+	})
 }
 
 func (r *schemaRepo) FindByID(id int) (*model.Schema, error) {
@@ -53,13 +70,57 @@ func (r *schemaRepo) Update(s *model.Schema) error {
 	return r.db.Save(s).Error
 }
 
+func (r *schemaRepo) UpdateWithVersion(s *model.Schema, v *model.SchemaVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the stored row serializes concurrent saves of the same schema
+		var stored model.Schema
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", s.ID).First(&stored).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&model.SchemaVersion{}).Where("schema_id = ?", s.ID).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		// Schemas saved before versions existed keep their stored state as version 1
+		if latest == 0 {
+			latest = 1
+			if err := tx.Create(&model.SchemaVersion{
+				SchemaID:  stored.ID,
+				Version:   latest,
+				AuthorID:  stored.UserID,
+				Name:      stored.Name,
+				Data:      stored.Data,
+				CreatedAt: stored.UpdatedAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(s).Error; err != nil {
+			return err
+		}
+
+		v.SchemaID = s.ID
+		v.Version = latest + 1
+		v.Name = s.Name
+		v.Data = s.Data
+		return tx.Create(v).Error
+	})
+}
+
+// Delete removes an owned schema together with its versions
 func (r *schemaRepo) Delete(id int, userID int) error {
-	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Schema{})
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return errors.New("schema not found or unauthorized")
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Schema{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("schema not found or unauthorized")
+		}
+		return tx.Where("schema_id = ?", id).Delete(&model.SchemaVersion{}).Error
+	})
 }
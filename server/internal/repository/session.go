@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(s *model.Session) error
+	FindByID(id int) (*model.Session, error)
+	FindByFamily(family string) (*model.Session, error)
+	// FindActiveByUserID lists the sessions that are neither revoked nor
+	// expired, most recently used first
+	FindActiveByUserID(userID int) ([]model.Session, error)
+	// Rotate replaces the refresh token hash, provided oldHash is still the
+	// current one. It reports false when another request rotated it first.
+	Rotate(id int, oldHash, newHash string, expiresAt time.Time) (bool, error)
+	Revoke(id int) error
+	RevokeAllForUser(userID int) error
+}
+
+type sessionRepo struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepo{db: db}
+}
+
+func (r *sessionRepo) Create(s *model.Session) error {
+	return r.db.Create(s).Error
+}
+
+func (r *sessionRepo) FindByID(id int) (*model.Session, error) {
+	var s model.Session
+	err := r.db.Where("id = ?", id).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &s, err
+}
+
+func (r *sessionRepo) FindByFamily(family string) (*model.Session, error) {
+	var s model.Session
+	err := r.db.Where("family = ?", family).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &s, err
+}
+
+func (r *sessionRepo) FindActiveByUserID(userID int) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepo) Rotate(id int, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	res := r.db.Model(&model.Session{}).
+		Where("id = ? AND refresh_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_hash": newHash,
+			"last_used_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *sessionRepo) Revoke(id int) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepo) RevokeAllForUser(userID int) error {
+	return r.db.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Dragodui/db-schemas-generator/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -15,7 +16,10 @@ type UserRepository interface {
 	FindByEmail(email string) (*model.User, error)
 	SetVerifyToken(email, token string, expiresAt time.Time) error
 	VerifyEmail(token string) error
-	GetByResetToken(token string) (*model.User, error)
+	// ResetPassword sets a new password and consumes the reset token in one
+	// statement, so a token cannot be used twice. It returns the user's ID,
+	// or 0 if the token is unknown or expired.
+	ResetPassword(token, newHash string) (int, error)
 	UpdatePassword(userID int, newHash string) error
 	SetResetToken(email, token string, expiresAt time.Time) error
 }
@@ -86,16 +90,6 @@ func (r *userRepo) VerifyEmail(token string) error {
 	return nil
 }
 
-func (r *userRepo) GetByResetToken(token string) (*model.User, error) {
-	var u model.User
-	err := r.db.Where("reset_token = ? AND reset_expires_at > ?", token, time.Now()).
-		First(&u).Error
-	if err != nil {
-		return nil, err
-	}
-	return &u, nil
-}
-
 func (r *userRepo) SetResetToken(email, token string, expiresAt time.Time) error {
 	return r.db.Model(&model.User{}).
 		Where("email = ?", email).
@@ -105,6 +99,24 @@ func (r *userRepo) SetResetToken(email, token string, expiresAt time.Time) error
 		}).Error
 }
 
+func (r *userRepo) ResetPassword(token, newHash string) (int, error) {
+	var u model.User
+	res := r.db.Model(&u).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("reset_token = ? AND reset_expires_at > ?", token, time.Now()).
+		Updates(map[string]interface{}{
+			"password_hash":    newHash,
+			"reset_token":      nil,
+			"reset_expires_at": nil,
+		})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return 0, nil
+	}
+	return u.ID, nil
+}
+
 func (r *userRepo) UpdatePassword(userID int, newHash string) error {
 	return r.db.Model(&model.User{}).Where("id = ?", userID).
 		Updates(map[string]interface{}{
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+	"gorm.io/gorm"
+)
+
+// SchemaVersionRepository reads schema snapshots. Versions are written by
+// SchemaRepository, in the same transaction as the change they record.
+type SchemaVersionRepository interface {
+	// FindBySchemaID lists a schema's versions, newest first, without their data
+	FindBySchemaID(schemaID int) ([]model.SchemaVersion, error)
+	FindByVersion(schemaID int, version int) (*model.SchemaVersion, error)
+}
+
+type schemaVersionRepo struct {
+	db *gorm.DB
+}
+
+func NewSchemaVersionRepository(db *gorm.DB) SchemaVersionRepository {
+	return &schemaVersionRepo{db: db}
+}
+
+func (r *schemaVersionRepo) FindBySchemaID(schemaID int) ([]model.SchemaVersion, error) {
+	var versions []model.SchemaVersion
+	err := r.db.Omit("data").Where("schema_id = ?", schemaID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *schemaVersionRepo) FindByVersion(schemaID int, version int) (*model.SchemaVersion, error) {
+	var v model.SchemaVersion
+	err := r.db.Where("schema_id = ? AND version = ?", schemaID, version).First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &v, err
+}
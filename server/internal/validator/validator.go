@@ -0,0 +1,361 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dragodui/db-schemas-generator/internal/exporter"
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+type Code string
+
+const (
+	CodeEmptyName             Code = "empty_name"
+	CodeDuplicateTable        Code = "duplicate_table"
+	CodeDuplicateColumn       Code = "duplicate_column"
+	CodeUnknownColumn         Code = "unknown_column"
+	CodeMissingTable          Code = "fk_missing_table"
+	CodeMissingColumn         Code = "fk_missing_column"
+	CodeColumnCount           Code = "fk_column_count"
+	CodeTypeMismatch          Code = "fk_type_mismatch"
+	CodeEnumWithoutValues     Code = "enum_without_values"
+	CodeAutoIncrementType     Code = "auto_increment_type"
+	CodeMultipleAutoIncrement Code = "multiple_auto_increment"
+	CodeMissingPrimaryKey     Code = "missing_primary_key"
+	CodeReservedWord          Code = "reserved_word"
+	CodeDuplicateIndex        Code = "duplicate_index"
+	CodeIndexOrder            Code = "index_order"
+	CodeEmptyCheck            Code = "empty_check"
+	CodeDuplicateConstraint   Code = "duplicate_constraint"
+	CodeInvalidType           Code = "invalid_type"
+)
+
+// Diagnostic is a single problem found in a schema, located by table and,
+// where it applies, column
+type Diagnostic struct {
+	Table    string   `json:"table,omitempty"`
+	Column   string   `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     Code     `json:"code"`
+	Message  string   `json:"message"`
+}
+
+// Validate checks schema data for structural problems. Errors make the schema
+// unusable for export, warnings point at likely design mistakes.
+func Validate(schema model.SchemaData) []Diagnostic {
+	v := &validation{diagnostics: []Diagnostic{}}
+
+	tables := make(map[string]*model.Table, len(schema.Tables))
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if table.Name == "" {
+			v.errorf(table.Name, "", CodeEmptyName, "table %d has no name", i+1)
+			continue
+		}
+		if _, exists := tables[table.Name]; exists {
+			v.errorf(table.Name, "", CodeDuplicateTable, "table %s is defined more than once", table.Name)
+			continue
+		}
+		tables[table.Name] = table
+		v.checkReserved(table.Name, "", "table "+table.Name)
+	}
+
+	indexNames := make(map[string]bool)
+	for _, table := range schema.Tables {
+		v.validateColumns(table)
+		v.validateForeignKeys(table, tables)
+		v.validateIndexes(table, indexNames)
+		v.validateConstraints(table, indexNames)
+	}
+
+	return v.diagnostics
+}
+
+// HasErrors reports whether any diagnostic has error severity
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type validation struct {
+	diagnostics []Diagnostic
+}
+
+func (v *validation) add(severity Severity, table, column string, code Code, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Table:    table,
+		Column:   column,
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validation) errorf(table, column string, code Code, format string, args ...interface{}) {
+	v.add(SeverityError, table, column, code, format, args...)
+}
+
+func (v *validation) warnf(table, column string, code Code, format string, args ...interface{}) {
+	v.add(SeverityWarning, table, column, code, format, args...)
+}
+
+func (v *validation) validateColumns(table model.Table) {
+	seen := make(map[string]bool, len(table.Columns))
+	var autoIncrement []string
+	hasPrimaryKey := false
+
+	for i, col := range table.Columns {
+		if col.Name == "" {
+			v.errorf(table.Name, "", CodeEmptyName, "column %d of table %s has no name", i+1, table.Name)
+			continue
+		}
+		if seen[col.Name] {
+			v.errorf(table.Name, col.Name, CodeDuplicateColumn, "column %s is defined more than once in table %s", col.Name, table.Name)
+			continue
+		}
+		seen[col.Name] = true
+		v.checkReserved(table.Name, col.Name, fmt.Sprintf("column %s.%s", table.Name, col.Name))
+
+		if col.PrimaryKey {
+			hasPrimaryKey = true
+		}
+
+		if !exporter.ValidType(col.Type) {
+			v.errorf(table.Name, col.Name, CodeInvalidType, "column %s.%s has invalid type %q; use a type name with optional numeric arguments, such as VARCHAR(255)", table.Name, col.Name, col.Type)
+		}
+
+		if strings.ToUpper(col.Type) == "ENUM" && len(col.EnumValues) == 0 {
+			v.errorf(table.Name, col.Name, CodeEnumWithoutValues, "enum column %s.%s has no values", table.Name, col.Name)
+		}
+
+		if col.AutoIncrement {
+			autoIncrement = append(autoIncrement, col.Name)
+			if !isIntegerType(col.Type) {
+				v.errorf(table.Name, col.Name, CodeAutoIncrementType, "column %s.%s is auto-increment but has non-integer type %s", table.Name, col.Name, col.Type)
+			}
+		}
+	}
+
+	if len(autoIncrement) > 1 {
+		v.errorf(table.Name, "", CodeMultipleAutoIncrement, "table %s has more than one auto-increment column: %s", table.Name, strings.Join(autoIncrement, ", "))
+	}
+
+	if !hasPrimaryKey && table.Name != "" {
+		v.warnf(table.Name, "", CodeMissingPrimaryKey, "table %s has no primary key", table.Name)
+	}
+}
+
+// checkReserved warns about names that exports quote but that every
+// hand-written query will have to quote as well
+func (v *validation) checkReserved(table, column, what string) {
+	name := table
+	if column != "" {
+		name = column
+	}
+	formats := exporter.ReservedIn(name)
+	if len(formats) == 0 {
+		return
+	}
+	dialects := make([]string, len(formats))
+	for i, f := range formats {
+		dialects[i] = string(f)
+	}
+	v.warnf(table, column, CodeReservedWord, "%s is a reserved word in %s", what, strings.Join(dialects, ", "))
+}
+
+func (v *validation) validateForeignKeys(table model.Table, tables map[string]*model.Table) {
+	for _, fk := range table.ForeignKeys {
+		source, targetCols := fk.SourceColumns(), fk.TargetColumns()
+		label := fmt.Sprintf("%s.%s", table.Name, strings.Join(source, ","))
+		// Diagnostics on composite keys point at their first column
+		column := ""
+		if len(source) > 0 {
+			column = source[0]
+		}
+
+		if len(source) == 0 {
+			v.errorf(table.Name, "", CodeUnknownColumn, "foreign key on %s has no columns", table.Name)
+			continue
+		}
+		for _, name := range source {
+			if findColumn(&table, name) == nil {
+				v.errorf(table.Name, name, CodeUnknownColumn, "foreign key on %s uses unknown column %s", table.Name, name)
+			}
+		}
+
+		target, ok := tables[fk.References.Table]
+		if !ok {
+			v.errorf(table.Name, column, CodeMissingTable, "foreign key %s references missing table %s", label, fk.References.Table)
+			continue
+		}
+
+		if len(targetCols) != len(source) {
+			v.errorf(table.Name, column, CodeColumnCount, "foreign key %s has %d columns but references %d", label, len(source), len(targetCols))
+			continue
+		}
+
+		for i, name := range targetCols {
+			ref := findColumn(target, name)
+			if ref == nil {
+				v.errorf(table.Name, source[i], CodeMissingColumn, "foreign key %s references missing column %s.%s", label, target.Name, name)
+				continue
+			}
+			col := findColumn(&table, source[i])
+			if col != nil && canonicalType(col.Type) != canonicalType(ref.Type) {
+				v.errorf(table.Name, source[i], CodeTypeMismatch, "foreign key column %s.%s has type %s but %s.%s has type %s",
+					table.Name, col.Name, col.Type, target.Name, ref.Name, ref.Type)
+			}
+		}
+	}
+}
+
+// validateIndexes checks index columns and names. Postgres requires index
+// names to be unique across the whole schema, so seen is shared by all tables.
+func (v *validation) validateIndexes(table model.Table, seen map[string]bool) {
+	for i, idx := range table.Indexes {
+		label := idx.Name
+		if label == "" {
+			label = fmt.Sprintf("%d", i+1)
+		}
+
+		if idx.Name != "" {
+			if seen[idx.Name] {
+				v.errorf(table.Name, "", CodeDuplicateIndex, "index name %s is used more than once", idx.Name)
+			}
+			seen[idx.Name] = true
+		}
+
+		if len(idx.Columns) == 0 {
+			v.errorf(table.Name, "", CodeUnknownColumn, "index %s on %s has no columns", label, table.Name)
+		}
+		for _, col := range idx.Columns {
+			if findColumn(&table, col.Name) == nil {
+				v.errorf(table.Name, col.Name, CodeUnknownColumn, "index %s on %s uses unknown column %s", label, table.Name, col.Name)
+			}
+			if order := strings.ToUpper(col.Order); order != "" && order != "ASC" && order != "DESC" {
+				v.errorf(table.Name, col.Name, CodeIndexOrder, "index %s on %s has invalid order %s for column %s", label, table.Name, col.Order, col.Name)
+			}
+		}
+		for _, name := range idx.Include {
+			if findColumn(&table, name) == nil {
+				v.errorf(table.Name, name, CodeUnknownColumn, "index %s on %s includes unknown column %s", label, table.Name, name)
+			}
+		}
+	}
+}
+
+// validateConstraints checks UNIQUE and CHECK constraints. UNIQUE
+// constraints are backed by indexes, so their names share the index names.
+func (v *validation) validateConstraints(table model.Table, indexNames map[string]bool) {
+	names := make(map[string]bool)
+	claim := func(name string) {
+		if name == "" {
+			return
+		}
+		if names[name] {
+			v.errorf(table.Name, "", CodeDuplicateConstraint, "constraint name %s is used more than once in table %s", name, table.Name)
+		}
+		names[name] = true
+	}
+	for _, fk := range table.ForeignKeys {
+		claim(fk.Name)
+	}
+
+	for i, u := range table.Uniques {
+		label := u.Name
+		if label == "" {
+			label = fmt.Sprintf("%d", i+1)
+		}
+		claim(u.Name)
+		if u.Name != "" {
+			if indexNames[u.Name] {
+				v.errorf(table.Name, "", CodeDuplicateIndex, "index name %s is used more than once", u.Name)
+			}
+			indexNames[u.Name] = true
+		}
+
+		if len(u.Columns) == 0 {
+			v.errorf(table.Name, "", CodeUnknownColumn, "unique constraint %s on %s has no columns", label, table.Name)
+		}
+		for _, name := range u.Columns {
+			if findColumn(&table, name) == nil {
+				v.errorf(table.Name, name, CodeUnknownColumn, "unique constraint %s on %s uses unknown column %s", label, table.Name, name)
+			}
+		}
+	}
+
+	for i, ch := range table.Checks {
+		claim(ch.Name)
+		if strings.TrimSpace(ch.Expression) == "" {
+			label := ch.Name
+			if label == "" {
+				label = fmt.Sprintf("%d", i+1)
+			}
+			v.errorf(table.Name, "", CodeEmptyCheck, "check constraint %s on %s has no expression", label, table.Name)
+		}
+	}
+}
+
+func findColumn(table *model.Table, name string) *model.Column {
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			return &table.Columns[i]
+		}
+	}
+	return nil
+}
+
+func isIntegerType(t string) bool {
+	switch canonicalType(t) {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT":
+		return true
+	}
+	return false
+}
+
+// canonicalType folds the spellings different dialects use for the same type,
+// so that a SERIAL key and an INTEGER reference compare equal
+func canonicalType(t string) string {
+	upper := strings.ToUpper(strings.TrimSpace(t))
+	if upper == "TINYINT(1)" {
+		return "BOOLEAN"
+	}
+	if i := strings.Index(upper, "("); i >= 0 {
+		upper = strings.TrimSpace(upper[:i])
+	}
+
+	switch upper {
+	case "INTEGER", "INT4", "SERIAL", "SERIAL4":
+		return "INT"
+	case "INT8", "BIGSERIAL", "SERIAL8":
+		return "BIGINT"
+	case "INT2", "SMALLSERIAL", "SERIAL2":
+		return "SMALLINT"
+	case "BOOL":
+		return "BOOLEAN"
+	case "CHARACTER VARYING":
+		return "VARCHAR"
+	case "CHARACTER":
+		return "CHAR"
+	case "NUMERIC", "DEC":
+		return "DECIMAL"
+	case "DOUBLE PRECISION", "FLOAT8":
+		return "DOUBLE"
+	case "TIMESTAMP WITH TIME ZONE":
+		return "TIMESTAMPTZ"
+	default:
+		return upper
+	}
+}
@@ -0,0 +1,170 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Dragodui/db-schemas-generator/internal/model"
+)
+
+// located is a diagnostic without its message
+type located struct {
+	Severity Severity
+	Table    string
+	Column   string
+	Code     Code
+}
+
+func users() model.Table {
+	return model.Table{Name: "users", Columns: []model.Column{
+		{Name: "id", Type: "SERIAL", PrimaryKey: true, AutoIncrement: true},
+		{Name: "email", Type: "VARCHAR(255)"},
+	}}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		tables []model.Table
+		want   []located
+	}{
+		{
+			name:   "valid schema",
+			tables: []model.Table{users()},
+		},
+		{
+			name:   "empty and duplicate table names",
+			tables: []model.Table{users(), users(), {Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}}}},
+			want: []located{
+				{SeverityError, "users", "", CodeDuplicateTable},
+				{SeverityError, "", "", CodeEmptyName},
+			},
+		},
+		{
+			name: "duplicate and unnamed columns",
+			tables: []model.Table{{Name: "t", Columns: []model.Column{
+				{Name: "id", Type: "INT", PrimaryKey: true},
+				{Name: "id", Type: "TEXT"},
+				{Type: "TEXT"},
+			}}},
+			want: []located{
+				{SeverityError, "t", "id", CodeDuplicateColumn},
+				{SeverityError, "t", "", CodeEmptyName},
+			},
+		},
+		{
+			name: "column types",
+			tables: []model.Table{{Name: "t", Columns: []model.Column{
+				{Name: "id", Type: "TEXT", PrimaryKey: true, AutoIncrement: true},
+				{Name: "n", Type: "BIGINT", AutoIncrement: true},
+				{Name: "state", Type: "enum"},
+				{Name: "evil", Type: "TEXT); DROP TABLE users; --"},
+			}}},
+			want: []located{
+				{SeverityError, "t", "id", CodeAutoIncrementType},
+				{SeverityError, "t", "state", CodeEnumWithoutValues},
+				{SeverityError, "t", "evil", CodeInvalidType},
+				{SeverityError, "t", "", CodeMultipleAutoIncrement},
+			},
+		},
+		{
+			name:   "missing primary key",
+			tables: []model.Table{{Name: "log", Columns: []model.Column{{Name: "line", Type: "TEXT"}}}},
+			want:   []located{{SeverityWarning, "log", "", CodeMissingPrimaryKey}},
+		},
+		{
+			name: "reserved words",
+			tables: []model.Table{{Name: "order", Columns: []model.Column{
+				{Name: "id", Type: "INT", PrimaryKey: true},
+				{Name: "select", Type: "TEXT"},
+			}}},
+			want: []located{
+				{SeverityWarning, "order", "", CodeReservedWord},
+				{SeverityWarning, "order", "select", CodeReservedWord},
+			},
+		},
+		{
+			name: "foreign keys",
+			tables: []model.Table{users(), {
+				Name: "posts",
+				Columns: []model.Column{
+					{Name: "id", Type: "INT", PrimaryKey: true},
+					{Name: "author_id", Type: "INTEGER"},
+					{Name: "editor", Type: "TEXT"},
+				},
+				ForeignKeys: []model.ForeignKey{
+					// SERIAL and INTEGER are the same type
+					{Column: "author_id", References: model.Reference{Table: "users", Column: "id"}},
+					{Column: "editor", References: model.Reference{Table: "users", Column: "id"}},
+					{Column: "author_id", References: model.Reference{Table: "people", Column: "id"}},
+					{Column: "author_id", References: model.Reference{Table: "users", Column: "uid"}},
+					{Column: "reviewer_id", References: model.Reference{Table: "users", Column: "id"}},
+					{Columns: []string{"author_id", "editor"}, References: model.Reference{Table: "users", Column: "id"}},
+				},
+			}},
+			want: []located{
+				{SeverityError, "posts", "editor", CodeTypeMismatch},
+				{SeverityError, "posts", "author_id", CodeMissingTable},
+				{SeverityError, "posts", "author_id", CodeMissingColumn},
+				{SeverityError, "posts", "reviewer_id", CodeUnknownColumn},
+				{SeverityError, "posts", "author_id", CodeColumnCount},
+			},
+		},
+		{
+			name: "indexes and constraints",
+			tables: []model.Table{
+				{
+					Name:    "a",
+					Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}},
+					Indexes: []model.Index{
+						{Name: "idx", Columns: []model.IndexColumn{{Name: "id", Order: "UP"}}},
+						{Columns: []model.IndexColumn{{Name: "missing"}}},
+					},
+					Uniques: []model.Unique{{Name: "dup", Columns: []string{"id"}}},
+					Checks: []model.Check{
+						{Name: "dup", Expression: "id > 0"},
+						{Name: "blank", Expression: " "},
+					},
+				},
+				{
+					Name:    "b",
+					Columns: []model.Column{{Name: "id", Type: "INT", PrimaryKey: true}},
+					Indexes: []model.Index{{Name: "idx", Columns: []model.IndexColumn{{Name: "id"}}}},
+				},
+			},
+			want: []located{
+				{SeverityError, "a", "id", CodeIndexOrder},
+				{SeverityError, "a", "missing", CodeUnknownColumn},
+				{SeverityError, "a", "", CodeDuplicateConstraint},
+				{SeverityError, "a", "", CodeEmptyCheck},
+				{SeverityError, "b", "", CodeDuplicateIndex},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := Validate(model.SchemaData{Tables: tt.tables})
+			got := []located{}
+			for _, d := range diagnostics {
+				if d.Message == "" {
+					t.Errorf("%s diagnostic has no message", d.Code)
+				}
+				got = append(got, located{d.Severity, d.Table, d.Column, d.Code})
+			}
+			want := tt.want
+			if want == nil {
+				want = []located{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("diagnostics:\n%v\nwant:\n%v", got, want)
+			}
+
+			wantErrors := false
+			for _, d := range want {
+				wantErrors = wantErrors || d.Severity == SeverityError
+			}
+			if HasErrors(diagnostics) != wantErrors {
+				t.Errorf("HasErrors = %v, want %v", !wantErrors, wantErrors)
+			}
+		})
+	}
+}